@@ -1,6 +1,7 @@
 package anomalo
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -46,11 +47,10 @@ func TestPostBody(t *testing.T) {
 
 	defer server.Close()
 
-	checkCadence := "Daily"
 	req := ConfigureTableRequest{
 		TableID:          123,
-		Definition:       "defn",
-		CheckCadenceType: &checkCadence,
+		Definition:       Set("defn"),
+		CheckCadenceType: Set("Daily"),
 	}
 
 	fakeAnomalo.Host = server.URL
@@ -69,6 +69,46 @@ func TestAnomaloBadRequest(t *testing.T) {
 	assert.Equal(t, `["API Error", "API Error 2"]`, err.Error())
 }
 
+func TestAnomaloTooManyRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	fakeAnomalo.Host = server.URL
+	_, err := fakeAnomalo.Ping()
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "5", apiErr.RetryAfter)
+	assert.Equal(t, "Too many requests, retry after: 5 seconds", err.Error())
+}
+
+func TestDecodeModes(t *testing.T) {
+	server := setupServer(t, "ping", `{"ping": "pong", "new_field": 1}`, http.StatusOK)
+	defer server.Close()
+
+	client := Client{Host: server.URL}
+	resp, err := client.Ping()
+	assert.Nil(t, err)
+	assert.Equal(t, "pong", resp.Ping)
+
+	client.DecodeMode = DecodeStrict
+	_, err = client.Ping()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "new_field")
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	server := setupServer(t, "ping", `{"ping": "pong"}`, http.StatusOK)
+	defer server.Close()
+
+	client := Client{Host: server.URL, MaxResponseBytes: 4}
+	_, err := client.Ping()
+	var maxBytesErr *http.MaxBytesError
+	assert.True(t, errors.As(err, &maxBytesErr))
+}
+
 func TestHttpError(t *testing.T) {
 	server := setupServer(t, "ping", `{"ping": "pong"}"`, http.StatusOK) // Valid server
 	server.Close()                                                       // No defer
@@ -78,6 +118,18 @@ func TestHttpError(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestCheckMatchesRequest(t *testing.T) {
+	check := &Check{Config: CheckConfig{Check: "AcceptedValuesCheck",
+		Params: map[string]interface{}{"column_name": "status", "values": []interface{}{"open"}}}}
+	req := CreateCheckRequest{CheckType: "AcceptedValuesCheck",
+		Params: map[string]string{"column_name": "status", "values": `["open"]`}}
+	assert.True(t, checkMatchesRequest(check, req))
+
+	req.Params["values"] = `["open","closed"]`
+	assert.False(t, checkMatchesRequest(check, req))
+	assert.False(t, checkMatchesRequest(check, CreateCheckRequest{CheckType: "NullCheck"}))
+}
+
 func TestLoadClientNoCreds(t *testing.T) {
 	client, err := CreateClient()
 	assert.Nil(t, client)
@@ -0,0 +1,212 @@
+package anomalo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// BackfillOptions Controls which intervals Backfill re-runs and how.
+type BackfillOptions struct {
+	// Start & End Intervals whose time period overlaps [Start, End) are re-run.
+	Start time.Time
+	End   time.Time
+
+	// CheckIDs, Force, RespectSkipExpr & ExecutionPriority Are passed on to
+	// every RunChecksRequest.
+	CheckIDs          []string
+	Force             bool
+	RespectSkipExpr   bool
+	ExecutionPriority string
+
+	// Concurrency & MaxRetries Behave as in BulkOptions.
+	Concurrency int
+	MaxRetries  int
+
+	// CheckpointPath If set, each interval that runs successfully is recorded
+	// in this JSON file, and intervals already recorded there are skipped. Use
+	// the same path to resume an interrupted backfill.
+	CheckpointPath string
+
+	// Progress If set, is called after each interval finishes or is skipped.
+	// Calls are serialized.
+	Progress func(BackfillProgress)
+}
+
+// BackfillProgress Reported to BackfillOptions.Progress after each interval.
+type BackfillProgress struct {
+	Interval Interval
+	Response *RunChecksResponse
+	Err      error
+	// Skipped True when the checkpoint shows the interval already ran.
+	Skipped bool
+	// Done & Total How many intervals have finished or been skipped so far, out
+	// of how many.
+	Done  int
+	Total int
+}
+
+// BackfillCheckpoint The contents of a backfill checkpoint file.
+type BackfillCheckpoint struct {
+	TableID              int   `json:"table_id"`
+	CompletedIntervalIDs []int `json:"completed_interval_ids"`
+}
+
+// BackfillResult The intervals Backfill found, and the outcome for each in
+// the same order.
+type BackfillResult struct {
+	Intervals []Interval
+	Results   []BulkResult[RunChecksResponse]
+}
+
+// Backfill Re-runs checks on every interval of a table between opts.Start and
+// opts.End, e.g. after fixing bad data. Intervals are run concurrently, and
+// every interval is attempted even if others fail; a *BulkError is returned if
+// any failed.
+//
+// Cancelling `ctx` stops new intervals from starting and cancels the
+// RunChecks calls in flight; those intervals fail with the context's error
+// and are not reported to opts.Progress. Combined with
+// opts.CheckpointPath, a later call picks up where the cancelled one left off.
+func (c *Client) Backfill(ctx context.Context, tableID int, opts BackfillOptions) (*BackfillResult, error) {
+	checkpoint := &BackfillCheckpoint{TableID: tableID}
+	if opts.CheckpointPath != "" {
+		var err error
+		checkpoint, err = loadBackfillCheckpoint(opts.CheckpointPath, tableID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	intervals, err := c.listIntervals(ctx, ListIntervalsRequest{TableID: tableID, Start: opts.Start, End: opts.End})
+	if err != nil {
+		return nil, err
+	}
+	completed := map[int]bool{}
+	for _, id := range checkpoint.CompletedIntervalIDs {
+		completed[id] = true
+	}
+
+	result := &BackfillResult{
+		Intervals: intervals.Intervals,
+		Results:   make([]BulkResult[RunChecksResponse], len(intervals.Intervals)),
+	}
+	reqs := make([]RunChecksRequest, len(intervals.Intervals))
+	pending := make([]bool, len(intervals.Intervals))
+	var mu sync.Mutex // Guards done, checkpoint and calls to Progress
+	done := 0
+	for i, interval := range intervals.Intervals {
+		reqs[i] = RunChecksRequest{
+			TableID:           tableID,
+			IntervalID:        interval.IntervalID,
+			CheckIDs:          opts.CheckIDs,
+			Force:             opts.Force,
+			RespectSkipExpr:   opts.RespectSkipExpr,
+			ExecutionPriority: opts.ExecutionPriority,
+		}
+		if completed[interval.IntervalID] {
+			result.Results[i].Skipped = true
+			done++
+			if opts.Progress != nil {
+				opts.Progress(BackfillProgress{Interval: interval, Skipped: true, Done: done, Total: len(reqs)})
+			}
+			continue
+		}
+		pending[i] = true
+	}
+
+	bulkOpts := BulkOptions{Concurrency: opts.Concurrency, MaxRetries: opts.MaxRetries}
+	intervalIndex := map[int]int{}
+	for i, interval := range intervals.Intervals {
+		intervalIndex[interval.IntervalID] = i
+	}
+	attempts := map[int]int{}
+	run := func(req RunChecksRequest) (*RunChecksResponse, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := c.runChecks(ctx, req)
+		if err != nil && ctx.Err() != nil {
+			return nil, err // Cancelled in flight, so treated as not started
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		// Rate limited attempts that runBulk will retry are not reported
+		attempt := attempts[req.IntervalID]
+		attempts[req.IntervalID]++
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests && attempt < bulkOpts.maxRetries() {
+			return resp, err
+		}
+
+		if err == nil && opts.CheckpointPath != "" {
+			checkpoint.CompletedIntervalIDs = append(checkpoint.CompletedIntervalIDs, req.IntervalID)
+			if saveErr := checkpoint.save(opts.CheckpointPath); saveErr != nil {
+				err = fmt.Errorf("interval %d ran but the checkpoint was not saved. %w", req.IntervalID, saveErr)
+			}
+		}
+		done++
+		if opts.Progress != nil {
+			opts.Progress(BackfillProgress{
+				Interval: intervals.Intervals[intervalIndex[req.IntervalID]],
+				Response: resp,
+				Err:      err,
+				Done:     done,
+				Total:    len(reqs),
+			})
+		}
+		return resp, err
+	}
+	runBulk(reqs, pending, result.Results, bulkOpts, run)
+	return result, bulkError(result.Results)
+}
+
+// loadBackfillCheckpoint Reads the checkpoint at `path`, or starts a new one
+// if the file does not exist yet.
+func loadBackfillCheckpoint(path string, tableID int) (*BackfillCheckpoint, error) {
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &BackfillCheckpoint{TableID: tableID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var checkpoint BackfillCheckpoint
+	if err := json.Unmarshal(contents, &checkpoint); err != nil {
+		return nil, fmt.Errorf("unable to parse backfill checkpoint %s. %w", path, err)
+	}
+	if checkpoint.TableID != tableID {
+		return nil, fmt.Errorf("backfill checkpoint %s is for table %d, not table %d", path, checkpoint.TableID, tableID)
+	}
+	return &checkpoint, nil
+}
+
+// save Writes the checkpoint to a temporary file and renames it over `path`,
+// so an interrupted write never leaves a corrupt checkpoint behind.
+func (cp *BackfillCheckpoint) save(path string) error {
+	contents, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(append(contents, '\n'))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
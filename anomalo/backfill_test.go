@@ -0,0 +1,138 @@
+package anomalo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFakeBackfillServer(t *testing.T, failInterval int, ran *[]RunChecksRequest) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/get_table_intervals":
+			assert.Equal(t, "end=2023-01-04T00%3A00%3A00Z&start=2023-01-01T00%3A00%3A00Z&table_id=5", r.URL.RawQuery)
+			w.Write([]byte(`{"intervals": [{"interval_id": 1}, {"interval_id": 2}, {"interval_id": 3}]}`))
+		case "/api/public/v1/run_checks":
+			body, _ := io.ReadAll(r.Body)
+			var req RunChecksRequest
+			assert.Nil(t, json.Unmarshal(body, &req))
+			mu.Lock()
+			*ran = append(*ran, req)
+			mu.Unlock()
+			if req.IntervalID == failInterval {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprintf(w, `{"run_checks_job_id": "job-%d"}`, req.IntervalID)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestBackfillResumesFromCheckpoint(t *testing.T) {
+	var ran []RunChecksRequest
+	server := newFakeBackfillServer(t, 2, &ran)
+	defer server.Close()
+	client := &Client{Host: server.URL}
+
+	var progress []BackfillProgress
+	opts := BackfillOptions{
+		Start:          time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:            time.Date(2023, 1, 4, 0, 0, 0, 0, time.UTC),
+		Force:          true,
+		CheckpointPath: filepath.Join(t.TempDir(), "checkpoint.json"),
+		Progress:       func(p BackfillProgress) { progress = append(progress, p) },
+	}
+
+	result, err := client.Backfill(context.Background(), 5, opts)
+	var bulkErr *BulkError
+	assert.True(t, errors.As(err, &bulkErr))
+	assert.Equal(t, []int{1}, bulkErr.Failed)
+	assert.Equal(t, "job-1", result.Results[0].Response.RunChecksJobId)
+	assert.Len(t, ran, 3)
+	assert.True(t, ran[0].Force)
+	assert.Len(t, progress, 3)
+	assert.Equal(t, 3, progress[2].Done)
+	assert.Equal(t, 3, progress[2].Total)
+
+	contents, err := os.ReadFile(opts.CheckpointPath)
+	assert.Nil(t, err)
+	var checkpoint BackfillCheckpoint
+	assert.Nil(t, json.Unmarshal(contents, &checkpoint))
+	sort.Ints(checkpoint.CompletedIntervalIDs)
+	assert.Equal(t, BackfillCheckpoint{TableID: 5, CompletedIntervalIDs: []int{1, 3}}, checkpoint)
+
+	// Resuming only re-runs the interval that failed
+	ran, progress = nil, nil
+	result, err = client.Backfill(context.Background(), 5, opts)
+	assert.NotNil(t, err)
+	assert.Len(t, ran, 1)
+	assert.Equal(t, 2, ran[0].IntervalID)
+	assert.True(t, result.Results[0].Skipped)
+	assert.True(t, progress[0].Skipped)
+
+	// A checkpoint for another table is rejected
+	_, err = client.Backfill(context.Background(), 6, opts)
+	assert.NotNil(t, err)
+}
+
+func TestBackfillCancelled(t *testing.T) {
+	var ran []RunChecksRequest
+	server := newFakeBackfillServer(t, 0, &ran)
+	defer server.Close()
+	client := &Client{Host: server.URL}
+	opts := BackfillOptions{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 4, 0, 0, 0, 0, time.UTC),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.Backfill(ctx, 5, opts)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Empty(t, ran)
+}
+
+func TestBackfillCancelledInFlight(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/get_table_intervals":
+			w.Write([]byte(`{"intervals": [{"interval_id": 1}, {"interval_id": 2}]}`))
+		case "/api/public/v1/run_checks":
+			io.ReadAll(r.Body) // The server only notices the client leaving once the body is read
+			cancel()
+			<-r.Context().Done() // Only returns once the client gives up
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := &Client{Host: server.URL}
+
+	var progress []BackfillProgress
+	result, err := client.Backfill(ctx, 5, BackfillOptions{
+		Concurrency: 1,
+		Progress:    func(p BackfillProgress) { progress = append(progress, p) },
+	})
+	assert.NotNil(t, err)
+	for _, interval := range result.Results {
+		assert.True(t, errors.Is(interval.Err, context.Canceled))
+	}
+	assert.Empty(t, progress)
+}
@@ -0,0 +1,256 @@
+package anomalo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBulkConcurrency The number of requests bulk operations keep in
+	// flight when BulkOptions.Concurrency is not set.
+	DefaultBulkConcurrency = 4
+	// DefaultBulkMaxRetries How many times bulk operations retry a rate
+	// limited request when BulkOptions.MaxRetries is not set.
+	DefaultBulkMaxRetries = 3
+)
+
+// BulkOptions Controls how BulkCreateChecks, BulkDeleteChecks, BulkRunChecks
+// and AcknowledgeFailingRuns issue requests.
+type BulkOptions struct {
+	// Concurrency The maximum number of requests in flight at once.
+	Concurrency int
+	// MaxRetries How many times a request that Anomalo rate limits (HTTP 429)
+	// is retried. While any request is waiting out a Retry-After, no new
+	// requests are sent. Set to a negative number to disable retries.
+	MaxRetries int
+	// SkipExistingRefs Only used by BulkCreateChecks. When set, requests whose
+	// Ref already exists on their table are skipped instead of creating a
+	// duplicate check. So are requests that repeat the table and Ref of an
+	// earlier request in the same call; they get that request's outcome.
+	SkipExistingRefs bool
+}
+
+// BulkResult The outcome of one request in a bulk operation. Results are
+// returned in the same order as the requests.
+type BulkResult[T any] struct {
+	Response *T
+	Err      error
+	// Skipped True when the request was not sent. See BulkOptions.SkipExistingRefs.
+	Skipped bool
+}
+
+// BulkError Returned by bulk operations when at least one request failed. The
+// individual errors are available on the BulkResult values.
+type BulkError struct {
+	Failed []int // Indexes of the failed requests
+	Total  int
+}
+
+func (e *BulkError) Error() string {
+	indexes := make([]string, len(e.Failed))
+	for i, index := range e.Failed {
+		indexes[i] = strconv.Itoa(index)
+	}
+	return fmt.Sprintf("%d of %d requests failed. failed indexes: %s", len(e.Failed), e.Total, strings.Join(indexes, ", "))
+}
+
+// BulkCreateChecks Creates many checks concurrently. Every request is
+// attempted; a failure does not stop the others.
+func (c *Client) BulkCreateChecks(reqs []CreateCheckRequest, opts BulkOptions) ([]BulkResult[CreateCheckResponse], error) {
+	results := make([]BulkResult[CreateCheckResponse], len(reqs))
+	pending := make([]bool, len(reqs))
+	for i := range pending {
+		pending[i] = true
+	}
+
+	// duplicateOf Maps the index of a request to that of an earlier request
+	// with the same table and Ref
+	duplicateOf := map[int]int{}
+	if opts.SkipExistingRefs {
+		type tableRef struct {
+			tableID int
+			ref     string
+		}
+		existing := map[int]map[string]Check{}
+		lookupErrs := map[int]error{}
+		first := map[tableRef]int{}
+		for i, req := range reqs {
+			if req.Ref == "" {
+				continue
+			}
+			checksByRef, ok := existing[req.TableID]
+			if !ok {
+				err, failed := lookupErrs[req.TableID]
+				if !failed {
+					var checks *GetChecksResponse
+					checks, err = c.GetChecks(req.TableID)
+					if err == nil {
+						checksByRef = map[string]Check{}
+						for _, check := range checks.Checks {
+							checksByRef[check.Ref] = check
+						}
+						existing[req.TableID] = checksByRef
+					} else {
+						err = fmt.Errorf("unable to look up existing checks for table %d. %w", req.TableID, err)
+						lookupErrs[req.TableID] = err
+					}
+				}
+				if err != nil {
+					results[i].Err = err
+					pending[i] = false
+					continue
+				}
+			}
+			if check, ok := checksByRef[req.Ref]; ok {
+				results[i] = BulkResult[CreateCheckResponse]{
+					Response: &CreateCheckResponse{
+						CheckID:       check.CheckID,
+						CheckRef:      check.Ref,
+						CheckStaticId: check.CheckStaticID,
+					},
+					Skipped: true,
+				}
+				pending[i] = false
+				continue
+			}
+			key := tableRef{req.TableID, req.Ref}
+			if j, ok := first[key]; ok {
+				duplicateOf[i] = j
+				pending[i] = false
+				continue
+			}
+			first[key] = i
+		}
+	}
+
+	runBulk(reqs, pending, results, opts, c.CreateCheck)
+	for i, j := range duplicateOf {
+		if results[j].Err != nil {
+			results[i].Err = fmt.Errorf("request %d with the same ref failed. %w", j, results[j].Err)
+		} else {
+			results[i] = BulkResult[CreateCheckResponse]{Response: results[j].Response, Skipped: true}
+		}
+	}
+	return results, bulkError(results)
+}
+
+// BulkDeleteChecks Deletes many checks concurrently. Every request is
+// attempted; a failure does not stop the others.
+func (c *Client) BulkDeleteChecks(reqs []DeleteCheckRequest, opts BulkOptions) ([]BulkResult[DeleteCheckResponse], error) {
+	results := make([]BulkResult[DeleteCheckResponse], len(reqs))
+	runBulk(reqs, nil, results, opts, c.DeleteCheck)
+	return results, bulkError(results)
+}
+
+// BulkRunChecks Runs checks for many tables or intervals concurrently. Every
+// request is attempted; a failure does not stop the others.
+func (c *Client) BulkRunChecks(reqs []RunChecksRequest, opts BulkOptions) ([]BulkResult[RunChecksResponse], error) {
+	results := make([]BulkResult[RunChecksResponse], len(reqs))
+	runBulk(reqs, nil, results, opts, c.RunChecks)
+	return results, bulkError(results)
+}
+
+// runBulk Calls `call` for every request whose `pending` entry is true (or
+// every request if `pending` is nil) with bounded concurrency, storing the
+// outcome in `results`.
+func runBulk[Req, Resp any](
+	reqs []Req,
+	pending []bool,
+	results []BulkResult[Resp],
+	opts BulkOptions,
+	call func(Req) (*Resp, error),
+) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBulkConcurrency
+	}
+	maxRetries := opts.maxRetries()
+
+	limiter := &rateLimitGate{}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				for attempt := 0; ; attempt++ {
+					limiter.wait()
+					results[i].Response, results[i].Err = call(reqs[i])
+					var apiErr *APIError
+					if attempt >= maxRetries || !errors.As(results[i].Err, &apiErr) ||
+						apiErr.StatusCode != http.StatusTooManyRequests {
+						break
+					}
+					limiter.pause(retryAfter(apiErr))
+				}
+			}
+		}()
+	}
+	for i := range reqs {
+		if pending == nil || pending[i] {
+			indexes <- i
+		}
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+func (o BulkOptions) maxRetries() int {
+	if o.MaxRetries == 0 {
+		return DefaultBulkMaxRetries
+	}
+	return o.MaxRetries
+}
+
+func bulkError[Resp any](results []BulkResult[Resp]) error {
+	var failed []int
+	for i, result := range results {
+		if result.Err != nil {
+			failed = append(failed, i)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return &BulkError{Failed: failed, Total: len(results)}
+}
+
+// retryAfter Parses the Retry-After header of a rate limited response. Anomalo
+// sends a number of seconds; anything else falls back to one second.
+func retryAfter(err *APIError) time.Duration {
+	seconds, parseErr := strconv.Atoi(strings.TrimSpace(err.RetryAfter))
+	if parseErr != nil || seconds < 0 {
+		return time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// rateLimitGate Lets bulk workers share a rate limit. Once any worker is told
+// to back off, every worker waits until the back off has passed.
+type rateLimitGate struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+func (g *rateLimitGate) pause(d time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if until := time.Now().Add(d); until.After(g.until) {
+		g.until = until
+	}
+}
+
+func (g *rateLimitGate) wait() {
+	g.mu.Lock()
+	until := g.until
+	g.mu.Unlock()
+	if d := time.Until(until); d > 0 {
+		time.Sleep(d)
+	}
+}
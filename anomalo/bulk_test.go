@@ -0,0 +1,170 @@
+package anomalo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkCreateChecks(t *testing.T) {
+	var mu sync.Mutex
+	created := map[string]int{}
+	rateLimited := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/public/v1/get_checks_for_table":
+			w.Write([]byte(`{"checks": [{"check_id": 10, "check_static_id": 11, "ref": "existing"}]}`))
+		case "/api/public/v1/create_check":
+			var req CreateCheckRequest
+			body, _ := io.ReadAll(r.Body)
+			assert.Nil(t, json.Unmarshal(body, &req))
+			if req.Ref == "limited" && !rateLimited {
+				rateLimited = true
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			if req.Ref == "bad" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`bad check`))
+				return
+			}
+			created[req.Ref]++
+			fmt.Fprintf(w, `{"check_id": %d, "ref": %q}`, len(created), req.Ref)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	reqs := []CreateCheckRequest{
+		{TableID: 1, CheckType: "NullCheck", Ref: "existing"},
+		{TableID: 1, CheckType: "NullCheck", Ref: "new"},
+		{TableID: 1, CheckType: "NullCheck", Ref: "bad"},
+		{TableID: 1, CheckType: "NullCheck", Ref: "limited"},
+	}
+	results, err := client.BulkCreateChecks(reqs, BulkOptions{Concurrency: 2, SkipExistingRefs: true})
+
+	var bulkErr *BulkError
+	assert.True(t, errors.As(err, &bulkErr))
+	assert.Equal(t, []int{2}, bulkErr.Failed)
+
+	assert.True(t, results[0].Skipped)
+	assert.Equal(t, 10, results[0].Response.CheckID)
+	assert.Equal(t, "new", results[1].Response.CheckRef)
+	assert.Equal(t, "bad check", results[2].Err.Error())
+	assert.Equal(t, "limited", results[3].Response.CheckRef)
+	assert.Equal(t, map[string]int{"new": 1, "limited": 1}, created)
+}
+
+func TestBulkDeleteChecks(t *testing.T) {
+	server := setupServer(t, "delete_check", `{"deleted_count": 1}`, http.StatusOK)
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	results, err := client.BulkDeleteChecks([]DeleteCheckRequest{{TableID: 1, CheckID: 2}, {TableID: 1, CheckID: 3}}, BulkOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.Equal(t, 1, result.Response.DeletedCount)
+	}
+}
+
+func TestBulkCreateChecksDuplicateRefs(t *testing.T) {
+	var mu sync.Mutex
+	created := map[string]int{}
+	lookups := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/public/v1/get_checks_for_table":
+			tableID := r.URL.Query().Get("table_id")
+			lookups[tableID]++
+			if tableID == "2" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(`{"checks": []}`))
+		case "/api/public/v1/create_check":
+			var req CreateCheckRequest
+			body, _ := io.ReadAll(r.Body)
+			assert.Nil(t, json.Unmarshal(body, &req))
+			if req.Ref == "bad" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`bad check`))
+				return
+			}
+			created[fmt.Sprintf("%d:%s", req.TableID, req.Ref)]++
+			fmt.Fprintf(w, `{"check_id": %d, "ref": %q}`, len(created), req.Ref)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	results, err := client.BulkCreateChecks([]CreateCheckRequest{
+		{TableID: 1, Ref: "a"},
+		{TableID: 1, Ref: "a"},
+		{TableID: 3, Ref: "a"},
+		{TableID: 1, Ref: "bad"},
+		{TableID: 1, Ref: "bad"},
+		{TableID: 2, Ref: "b"},
+		{TableID: 2, Ref: "c"},
+	}, BulkOptions{Concurrency: 3, SkipExistingRefs: true})
+
+	var bulkErr *BulkError
+	assert.True(t, errors.As(err, &bulkErr))
+	assert.Equal(t, []int{3, 4, 5, 6}, bulkErr.Failed)
+	assert.Equal(t, map[string]int{"1:a": 1, "3:a": 1}, created, "a ref is created once per table")
+	assert.False(t, results[0].Skipped)
+	assert.True(t, results[1].Skipped)
+	assert.Same(t, results[0].Response, results[1].Response)
+	assert.Contains(t, results[4].Err.Error(), "request 3 with the same ref failed")
+	assert.Equal(t, 1, lookups["2"], "a failed lookup is not repeated")
+	assert.Equal(t, results[5].Err, results[6].Err)
+}
+
+func TestBulkRunChecks(t *testing.T) {
+	var mu sync.Mutex
+	var ran []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/public/v1/run_checks", r.URL.Path)
+		var req RunChecksRequest
+		body, _ := io.ReadAll(r.Body)
+		assert.Nil(t, json.Unmarshal(body, &req))
+		if req.TableID == 2 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`table not found`))
+			return
+		}
+		mu.Lock()
+		ran = append(ran, req.TableID)
+		mu.Unlock()
+		fmt.Fprintf(w, `{"run_checks_job_id": "job-%d"}`, req.TableID)
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	results, err := client.BulkRunChecks([]RunChecksRequest{{TableID: 1}, {TableID: 2}, {TableID: 3}}, BulkOptions{Concurrency: 2})
+
+	var bulkErr *BulkError
+	assert.True(t, errors.As(err, &bulkErr))
+	assert.Equal(t, []int{1}, bulkErr.Failed)
+	assert.Equal(t, 3, bulkErr.Total)
+	assert.ElementsMatch(t, []int{1, 3}, ran)
+	assert.Equal(t, "job-1", results[0].Response.RunChecksJobId)
+	assert.Equal(t, "table not found", results[1].Err.Error())
+	assert.Equal(t, "job-3", results[2].Response.RunChecksJobId)
+}
@@ -0,0 +1,182 @@
+package anomalo
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheableEndpoints Read-only endpoints whose responses may be served from a
+// ResponseCache.
+var cacheableEndpoints = map[string]bool{
+	"get_table_information":      true,
+	"get_checks_for_table":       true,
+	"list_notification_channels": true,
+	"list_warehouses":            true,
+	"list_labels":                true,
+}
+
+// tableScoped Implemented by requests and responses that belong to a single
+// table, so cached responses can be invalidated when that table changes.
+type tableScoped interface {
+	cacheTableID() int
+}
+
+func (r GetTableInformationRequest) cacheTableID() int { return r.TableID }
+func (r getChecksRequest) cacheTableID() int           { return r.TableID }
+func (r GetTableResponse) cacheTableID() int           { return r.ID }
+func (r ConfigureTableRequest) cacheTableID() int      { return r.TableID }
+func (r CreateCheckRequest) cacheTableID() int         { return r.TableID }
+func (r DeleteCheckRequest) cacheTableID() int         { return r.TableID }
+func (r CheckLabelsRequest) cacheTableID() int         { return r.TableID }
+func (r TableLabelsRequest) cacheTableID() int         { return r.TableID }
+func (r UpdateTriageStatusRequest) cacheTableID() int  { return r.TableID }
+
+// cacheTableID Returns the table a cached response belongs to, preferring the
+// request and falling back to the response. Returns 0 if neither is scoped to
+// a table.
+func cacheTableID(values ...interface{}) int {
+	for _, value := range values {
+		if scoped, ok := value.(tableScoped); ok {
+			if id := scoped.cacheTableID(); id != 0 {
+				return id
+			}
+		}
+	}
+	return 0
+}
+
+// cacheScope Converts a possibly nil request to an interface{} that is safe to
+// pass to cacheTableID.
+func cacheScope[Req any](req *Req) interface{} {
+	if req == nil {
+		return nil
+	}
+	return req
+}
+
+// cacheBypassKey Marks a context whose lookups skip cached responses.
+type cacheBypassKey struct{}
+
+// withoutCachedResponses Returns a context whose lookups always go to Anomalo.
+// Their fresh responses still replace what the cache holds.
+func withoutCachedResponses(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+// CacheStats Counters describing how a ResponseCache has been used.
+type CacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+}
+
+// ResponseCache An opt-in, in-memory TTL cache for read-heavy lookups. Assign
+// one to Client.Cache to enable it.
+//
+// Responses from GetTableInformation, GetChecks (and so GetCheckByStaticID and
+// GetCheckByRef), GetNotificationChannels, ListWarehouses and ListLabels are
+// cached, keyed by endpoint and parameters. ConfigureTable, CreateCheck,
+// DeleteCheck and the label attach and detach calls invalidate the cached
+// responses for their table, and changing organizations clears the cache
+// entirely.
+//
+// A ResponseCache is safe for concurrent use, and may be shared between
+// Clients that use the same API key.
+type ResponseCache struct {
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	hits    uint64
+	misses  uint64
+}
+
+type cacheEntry struct {
+	body    []byte
+	tableID int
+	expires time.Time
+}
+
+// NewResponseCache Creates a ResponseCache whose entries expire after `ttl`.
+func NewResponseCache(ttl time.Duration) *ResponseCache {
+	return &ResponseCache{TTL: ttl}
+}
+
+func (rc *ResponseCache) get(key string) ([]byte, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, ok := rc.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		delete(rc.entries, key)
+		rc.misses++
+		return nil, false
+	}
+	rc.hits++
+	return entry.body, true
+}
+
+func (rc *ResponseCache) put(key string, body []byte, tableID int) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.entries == nil {
+		rc.entries = map[string]cacheEntry{}
+	}
+	rc.entries[key] = cacheEntry{body: body, tableID: tableID, expires: time.Now().Add(rc.TTL)}
+}
+
+// invalidateAfter Drops the cached responses made stale by a successful call
+// to the mutating endpoint `endpoint`.
+func (rc *ResponseCache) invalidateAfter(endpoint string, req interface{}) {
+	if strings.HasPrefix(endpoint, "warehouse") {
+		rc.invalidateEndpoint("list_warehouses")
+	}
+	switch endpoint {
+	case "create_label":
+		rc.invalidateEndpoint("list_labels")
+	case "delete_label":
+		// The label is detached from every check and table
+		rc.invalidateEndpoint("list_labels")
+		rc.invalidateEndpoint("get_checks_for_table")
+		rc.invalidateEndpoint("get_table_information")
+	}
+	if id := cacheTableID(req); id != 0 {
+		rc.InvalidateTable(id)
+	}
+}
+
+func (rc *ResponseCache) invalidateEndpoint(endpoint string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for key := range rc.entries {
+		if strings.HasPrefix(key, endpoint+"?") {
+			delete(rc.entries, key)
+		}
+	}
+}
+
+// InvalidateTable Drops every cached response that belongs to table `tableID`.
+func (rc *ResponseCache) InvalidateTable(tableID int) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for key, entry := range rc.entries {
+		if entry.tableID == tableID {
+			delete(rc.entries, key)
+		}
+	}
+}
+
+// Clear Drops every cached response. Statistics are kept.
+func (rc *ResponseCache) Clear() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries = nil
+}
+
+// Stats Returns hit and miss counts since the cache was created.
+func (rc *ResponseCache) Stats() CacheStats {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return CacheStats{Hits: rc.hits, Misses: rc.misses, Entries: len(rc.entries)}
+}
@@ -0,0 +1,65 @@
+package anomalo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseCache(t *testing.T) {
+	var checkCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/get_checks_for_table":
+			atomic.AddInt32(&checkCalls, 1)
+			w.Write([]byte(`{"checks": [{"check_id": 1, "check_static_id": 2, "ref": "ref"}]}`))
+		case "/api/public/v1/create_check":
+			w.Write([]byte(`{"check_id": 3}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL, Cache: NewResponseCache(time.Minute)}
+
+	check, err := client.GetCheckByStaticID(1, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, check.CheckID)
+	check, err = client.GetCheckByRef(1, "ref")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, check.CheckID)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&checkCalls))
+
+	// A different table is a different cache key
+	_, err = client.GetChecks(2)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&checkCalls))
+
+	// Creating a check on table 1 invalidates only table 1
+	_, err = client.CreateCheck(CreateCheckRequest{TableID: 1})
+	assert.Nil(t, err)
+	_, err = client.GetChecks(1)
+	assert.Nil(t, err)
+	_, err = client.GetChecks(2)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&checkCalls))
+
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 3, Entries: 2}, client.Cache.Stats())
+}
+
+func TestResponseCacheExpiry(t *testing.T) {
+	server := setupServer(t, "list_warehouses", `{"warehouses": [{"id": 1}]}`, http.StatusOK)
+	defer server.Close()
+
+	client := &Client{Host: server.URL, Cache: NewResponseCache(0)}
+	for i := 0; i < 2; i++ {
+		_, err := client.ListWarehouses()
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, uint64(0), client.Cache.Stats().Hits)
+}
@@ -0,0 +1,324 @@
+package anomalo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// RedactedValue Replaces secrets in recorded cassettes.
+const RedactedValue = "REDACTED"
+
+// Cassette A recording of Anomalo API interactions, stored as a JSON fixture
+// file. Use a Recorder to create one and a Replayer to serve it back.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction One recorded request and its response.
+type Interaction struct {
+	Method string `json:"method"`
+	// Endpoint The API endpoint, relative to /api/public/v1/.
+	Endpoint string `json:"endpoint"`
+	// Params The normalized query parameters or JSON body. See normalizeParams.
+	Params  string            `json:"params"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Response The response body when it is JSON. Other bodies, like some
+	// error messages, are stored in ResponseText.
+	Response     json.RawMessage `json:"response,omitempty"`
+	ResponseText string          `json:"response_text,omitempty"`
+}
+
+// LoadCassette Reads a cassette from a fixture file.
+func LoadCassette(path string) (*Cassette, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(contents, &cassette); err != nil {
+		return nil, fmt.Errorf("unable to parse cassette %s. %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// Save Writes the cassette to a fixture file.
+func (c *Cassette) Save(path string) error {
+	contents, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(contents, '\n'), 0o644)
+}
+
+// Recorder An http.RoundTripper that sends requests to Anomalo and records
+// each interaction. Plug it into a Client with ClientProvider, then call Save
+// once the interactions of interest have run.
+//
+// The bearer token is never recorded. Values of any JSON field or query
+// parameter named in RedactFields are replaced with RedactedValue in both
+// requests and responses.
+type Recorder struct {
+	// Transport Sends the real requests. Defaults to http.DefaultTransport.
+	Transport    http.RoundTripper
+	RedactFields []string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// ClientProvider Returns an HttpClientProvider whose clients record through r.
+func (r *Recorder) ClientProvider() HttpClientProvider {
+	return func() *http.Client { return &http.Client{Transport: r} }
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		closeBody(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	closeBody(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	params, err := normalizeParams(req.URL.Query(), reqBody, r.RedactFields)
+	if err != nil {
+		return nil, err
+	}
+	interaction := Interaction{
+		Method:   req.Method,
+		Endpoint: cassetteEndpoint(req.URL),
+		Params:   params,
+		Status:   resp.StatusCode,
+	}
+	if json.Valid(respBody) {
+		interaction.Response = redactJSON(respBody, r.RedactFields)
+	} else {
+		interaction.ResponseText = string(respBody)
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		interaction.Headers = map[string]string{"Retry-After": retryAfter}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	return resp, nil
+}
+
+// Cassette Returns a copy of the interactions recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Save Writes the interactions recorded so far to a fixture file.
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
+
+// Replayer An http.RoundTripper that serves responses from a Cassette
+// without network access. Requests are matched on method, endpoint and
+// normalized parameters. When several interactions match, they are served in
+// recorded order, and the last one is repeated once the others are used up.
+// Requests with no match fail.
+type Replayer struct {
+	Cassette *Cassette
+	// RedactFields Must match the Recorder's, so that requests containing
+	// redacted values still match.
+	RedactFields []string
+
+	mu   sync.Mutex
+	used map[int]bool
+}
+
+// NewReplayer Creates a Replayer for the cassette stored at `path`.
+func NewReplayer(path string, redactFields ...string) (*Replayer, error) {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{Cassette: cassette, RedactFields: redactFields}, nil
+}
+
+// ClientProvider Returns an HttpClientProvider whose clients replay from r.
+func (r *Replayer) ClientProvider() HttpClientProvider {
+	return func() *http.Client { return &http.Client{Transport: r} }
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		closeBody(req.Body)
+	}
+	params, err := normalizeParams(req.URL.Query(), reqBody, r.RedactFields)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := cassetteEndpoint(req.URL)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.used == nil {
+		r.used = map[int]bool{}
+	}
+	match := -1
+	for i, interaction := range r.Cassette.Interactions {
+		if interaction.Method != req.Method || interaction.Endpoint != endpoint || interaction.Params != params {
+			continue
+		}
+		match = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("no recorded interaction for %s %s with params %s", req.Method, endpoint, params)
+	}
+	r.used[match] = true
+
+	interaction := r.Cassette.Interactions[match]
+	body := []byte(interaction.Response)
+	if len(body) == 0 {
+		body = []byte(interaction.ResponseText)
+	}
+	header := http.Header{"Content-Type": []string{"application/json"}}
+	for key, value := range interaction.Headers {
+		header.Set(key, value)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+		StatusCode:    interaction.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func cassetteEndpoint(u *url.URL) string {
+	path := u.Path
+	if i := strings.Index(path, "/api/public/v1/"); i >= 0 {
+		path = path[i+len("/api/public/v1/"):]
+	}
+	return path
+}
+
+// normalizeParams Produces a stable string for a request's parameters: the
+// query string with sorted keys, followed by the JSON body re-encoded with
+// sorted keys. Redacted fields are replaced in both.
+func normalizeParams(query url.Values, body []byte, redact []string) (string, error) {
+	for _, field := range redact {
+		if _, ok := query[field]; ok {
+			query[field] = []string{RedactedValue}
+		}
+	}
+	parts := []string{query.Encode()} // Encode sorts by key
+
+	if len(bytes.TrimSpace(body)) > 0 {
+		parsed, err := decodeJSONValue(body)
+		if err != nil {
+			return "", fmt.Errorf("unable to normalize request body. %w", err)
+		}
+		normalized, err := json.Marshal(redactValue(parsed, redactSet(redact)))
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, string(normalized))
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// redactJSON Replaces redacted fields in a valid JSON document.
+func redactJSON(body []byte, redact []string) json.RawMessage {
+	if len(redact) == 0 {
+		return body
+	}
+	parsed, err := decodeJSONValue(body)
+	if err != nil {
+		return body
+	}
+	redacted, err := json.Marshal(redactValue(parsed, redactSet(redact)))
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// decodeJSONValue Decodes a JSON document with numbers kept as json.Number,
+// so that re-encoding it doesn't round IDs above 2^53.
+func decodeJSONValue(body []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var parsed interface{}
+	if err := decoder.Decode(&parsed); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid data after the JSON value")
+	}
+	return parsed, nil
+}
+
+func redactSet(fields []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		set[field] = struct{}{}
+	}
+	return set
+}
+
+func redactValue(value interface{}, redact map[string]struct{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key := range v {
+			if _, ok := redact[key]; ok {
+				v[key] = RedactedValue
+			} else {
+				v[key] = redactValue(v[key], redact)
+			}
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i], redact)
+		}
+		return v
+	default:
+		return value
+	}
+}
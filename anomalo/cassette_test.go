@@ -0,0 +1,80 @@
+package anomalo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/get_table_information":
+			w.Write([]byte(`{"id": 5, "full_name": "wh.s.t", "description": "secret stuff"}`))
+		case "/api/public/v1/configure_table":
+			w.Write([]byte(`{"id": 5}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`not json`))
+		}
+	}))
+	defer server.Close()
+
+	recorder := &Recorder{RedactFields: []string{"description"}}
+	client := &Client{Host: server.URL, Token: "super-secret-token", ClientProvider: recorder.ClientProvider()}
+	_, err := client.GetTableInformation("wh.s.t")
+	assert.Nil(t, err)
+	_, err = client.ConfigureTable(ConfigureTableRequest{TableID: 5, Definition: Set("defn")})
+	assert.Nil(t, err)
+	_, err = client.Ping()
+	assert.NotNil(t, err)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	assert.Nil(t, recorder.Save(path))
+	contents, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(string(contents), "super-secret-token"))
+	assert.False(t, strings.Contains(string(contents), "secret stuff"))
+
+	replayer, err := NewReplayer(path, "description")
+	assert.Nil(t, err)
+	offline := &Client{Host: "https://anomalo.invalid", ClientProvider: replayer.ClientProvider()}
+
+	table, err := offline.GetTableInformation("wh.s.t")
+	assert.Nil(t, err)
+	assert.Equal(t, 5, table.ID)
+	assert.Equal(t, RedactedValue, table.Description)
+
+	configured, err := offline.ConfigureTable(ConfigureTableRequest{TableID: 5, Definition: Set("defn")})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, configured.ID)
+
+	_, err = offline.Ping()
+	assert.NotNil(t, err)
+	assert.Equal(t, "not json", err.Error())
+
+	_, err = offline.GetTableInformation("wh.s.other")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "no recorded interaction for GET get_table_information")
+}
+
+func TestCassetteKeepsLargeIDs(t *testing.T) {
+	body := []byte(`{"table_id": 12345678901234567, "description": "secret", "ratio": 0.5}`)
+
+	normalized, err := normalizeParams(url.Values{}, body, []string{"description"})
+	assert.Nil(t, err)
+	assert.Equal(t, ` {"description":"REDACTED","ratio":0.5,"table_id":12345678901234567}`, normalized)
+
+	redacted := redactJSON(body, []string{"description"})
+	assert.JSONEq(t, `{"table_id": 12345678901234567, "description": "REDACTED", "ratio": 0.5}`, string(redacted))
+	assert.Contains(t, string(redacted), "12345678901234567")
+
+	_, err = normalizeParams(url.Values{}, []byte(`{"a": 1} trailing`), nil)
+	assert.NotNil(t, err)
+}
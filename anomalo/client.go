@@ -7,12 +7,18 @@ package anomalo
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"unsafe"
 
 	"golang.org/x/exp/maps"
 )
@@ -21,6 +27,10 @@ import (
 // testing and for package users that need control over network requests.
 type HttpClientProvider func() *http.Client
 
+// DefaultMaxResponseBytes The largest response body the client will read when
+// Client.MaxResponseBytes is not set.
+const DefaultMaxResponseBytes int64 = 32 << 20
+
 var (
 	// ValidNotificationChannels A set of supported notification channel types
 	ValidNotificationChannels = map[string]struct{}{
@@ -34,13 +44,77 @@ var (
 	}
 )
 
+// DecodeMode Controls how response fields that the client does not know about
+// are handled.
+type DecodeMode int
+
+const (
+	// DecodeLenient Unknown response fields are ignored. This is the default.
+	DecodeLenient DecodeMode = iota
+	// DecodeStrict Unknown response fields cause the call to fail. Useful for
+	// contract tests that should notice when the Anomalo API changes.
+	DecodeStrict
+)
+
 // Client The Anomalo client - used to authenticate & make calls to the Anomalo
 // API.
+//
+// A Client is safe for concurrent use by multiple goroutines. Goroutines that
+// target different organizations should use ForOrganization.
 type Client struct {
 	Token          string `json:"Token,omitempty"`
 	Host           string `json:"Host,omitempty"`
 	ClientProvider HttpClientProvider
-	client         *http.Client
+	// MaxResponseBytes Responses larger than this fail with an
+	// *http.MaxBytesError. Defaults to DefaultMaxResponseBytes.
+	MaxResponseBytes int64
+	DecodeMode       DecodeMode
+	// Cache Optionally caches read-only lookups. See ResponseCache.
+	Cache *ResponseCache
+
+	// state Is created on first use and kept behind a pointer, so a Client
+	// can still be copied. Copies made after first use share it. Only access
+	// it through getState.
+	state *clientState
+}
+
+// clientState The parts of a Client that are shared between goroutines.
+type clientState struct {
+	clientOnce sync.Once
+	client     *http.Client
+	// orgMu Serializes organization switching, and guards activeOrg. See
+	// OrgScopedClient.
+	orgMu sync.Mutex
+	// activeOrg The organization this Client last changed to, or 0 if it has
+	// not changed organization.
+	activeOrg int64
+}
+
+// getState Returns the Client's state, creating it on first use. The state
+// pointer is loaded and set atomically instead of being held in an
+// atomic.Pointer, which would stop the Client from being copied.
+func (c *Client) getState() *clientState {
+	addr := (*unsafe.Pointer)(unsafe.Pointer(&c.state))
+	if state := atomic.LoadPointer(addr); state != nil {
+		return (*clientState)(state)
+	}
+	atomic.CompareAndSwapPointer(addr, nil, unsafe.Pointer(&clientState{}))
+	return (*clientState)(atomic.LoadPointer(addr))
+}
+
+// APIError Returned when Anomalo responds with a non-200 status code.
+type APIError struct {
+	StatusCode int
+	Body       string
+	// RetryAfter The value of the Retry-After header, if Anomalo sent one.
+	RetryAfter string
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == http.StatusTooManyRequests && e.RetryAfter != "" {
+		return fmt.Sprintf("Too many requests, retry after: %s seconds", e.RetryAfter)
+	}
+	return e.Body
 }
 
 func closeBody(body io.ReadCloser) {
@@ -52,50 +126,135 @@ func closeBody(body io.ReadCloser) {
 
 // Memo-ized client
 func (c *Client) getClient() *http.Client {
-	if c.client == nil {
+	state := c.getState()
+	state.clientOnce.Do(func() {
 		if c.ClientProvider != nil {
-			c.client = c.ClientProvider()
+			state.client = c.ClientProvider()
 		} else {
-			c.client = &http.Client{}
+			state.client = &http.Client{}
 		}
-	}
-	return c.client
+	})
+	return state.client
 }
 
 func (c *Client) buildUrl(endpoint string) string {
 	return fmt.Sprintf("%s/api/public/v1/%s", c.Host, endpoint)
 }
 
-func (c *Client) apiCall(endpoint string, method string) (*http.Response, error) {
-	return c.apiCallWithBody(endpoint, method, "{}")
+func (c *Client) maxResponseBytes() int64 {
+	if c.MaxResponseBytes > 0 {
+		return c.MaxResponseBytes
+	}
+	return DefaultMaxResponseBytes
 }
 
-// apiCallWithBody Builds an HTTP request to Anomalo with the given JSON
-// parameters. Encodes them in the request body for PUT and POST requests, and
-// encodes them as URL parameters for all other HTTP method types.
-func (c *Client) apiCallWithBody(endpoint string, method string, jsonParams string) (*http.Response, error) {
-	var req *http.Request
-	var err error
+// do Is the core of every API call. It encodes `req`, sends it to `endpoint`
+// and decodes the response into a new `Resp`. Pass a nil `req` for endpoints
+// that take no parameters.
+//
+// Errors returned by Anomalo are returned as an *APIError. All other errors
+// are wrapped with the endpoint that produced them.
+func do[Req, Resp any](ctx context.Context, c *Client, method string, endpoint string, req *Req) (*Resp, error) {
+	var query url.Values
+	body := []byte("{}")
 	if method == http.MethodPost || method == http.MethodPut {
-		req, err = http.NewRequest(method, c.buildUrl(endpoint), bytes.NewBuffer([]byte(jsonParams)))
-		if err != nil {
-			return nil, err
+		if req != nil {
+			var err error
+			body, err = json.Marshal(req)
+			if err != nil {
+				return nil, fmt.Errorf("%s: encoding request: %w", endpoint, err)
+			}
 		}
 	} else {
-		req, err = http.NewRequest(method, c.buildUrl(endpoint), nil)
+		var err error
+		query, err = encodeQuery(req)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%s: encoding query: %w", endpoint, err)
 		}
-		var parsed map[string]interface{}
-		err = json.Unmarshal([]byte(jsonParams), &parsed)
+	}
+
+	var cacheKey string
+	if c.Cache != nil && method == http.MethodGet && cacheableEndpoints[endpoint] {
+		cacheKey = endpoint + "?" + query.Encode()
+		// A bypassing lookup still stores its fresh response below
+		if ctx.Value(cacheBypassKey{}) == nil {
+			if cached, ok := c.Cache.get(cacheKey); ok {
+				return decodeResponse[Resp](c, endpoint, bytes.NewReader(cached))
+			}
+		}
+	}
+
+	resp, err := c.apiCallWithBody(ctx, endpoint, method, query, body)
+	if err != nil {
+		return nil, err
+	}
+	respBody := http.MaxBytesReader(nil, resp.Body, c.maxResponseBytes())
+	defer closeBody(respBody)
+
+	if cacheKey == "" {
+		data, err := decodeResponse[Resp](c, endpoint, respBody)
 		if err != nil {
 			return nil, err
 		}
-		params := req.URL.Query()
-		for key, value := range parsed {
-			params.Add(key, fmt.Sprintf("%v", value))
+		if c.Cache != nil && method != http.MethodGet {
+			c.Cache.invalidateAfter(endpoint, cacheScope(req))
 		}
-		req.URL.RawQuery = params.Encode()
+		return data, nil
+	}
+
+	raw, err := io.ReadAll(respBody)
+	if err != nil {
+		return nil, fmt.Errorf("%s: reading response: %w", endpoint, err)
+	}
+	data, err := decodeResponse[Resp](c, endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	c.Cache.put(cacheKey, raw, cacheTableID(cacheScope(req), data))
+	return data, nil
+}
+
+func decodeResponse[Resp any](c *Client, endpoint string, body io.Reader) (*Resp, error) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: reading response: %w", endpoint, err)
+	}
+	var data Resp
+	if err := json.NewDecoder(bytes.NewReader(raw)).Decode(&data); err != nil {
+		return nil, fmt.Errorf("%s: decoding response: %w", endpoint, err)
+	}
+	if c.DecodeMode == DecodeStrict {
+		unknown, err := unknownFields(raw, reflect.TypeOf(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: decoding response: %w", endpoint, err)
+		}
+		if len(unknown) > 0 {
+			return nil, fmt.Errorf("%s: decoding response: unknown field(s) %s", endpoint, strings.Join(unknown, ", "))
+		}
+	}
+	return &data, nil
+}
+
+// apiCallWithBody Builds an HTTP request to Anomalo. `jsonBody` is sent as the
+// request body for PUT and POST requests, and `query` is sent as URL
+// parameters for all other HTTP method types.
+func (c *Client) apiCallWithBody(
+	ctx context.Context,
+	endpoint string,
+	method string,
+	query url.Values,
+	jsonBody []byte,
+) (*http.Response, error) {
+	var body io.Reader
+	if method == http.MethodPost || method == http.MethodPut {
+		body = bytes.NewBuffer(jsonBody)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.buildUrl(endpoint), body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", endpoint, err)
+	}
+	if len(query) > 0 {
+		req.URL.RawQuery = query.Encode()
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.Token)
@@ -103,36 +262,25 @@ func (c *Client) apiCallWithBody(endpoint string, method string, jsonParams stri
 
 	resp, err := c.getClient().Do(req)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", endpoint, err)
 	}
 
 	if resp.StatusCode != 200 {
-		// Parse header for retry time
-		if retryAfter, ok := resp.Header["Retry-After"]; resp.StatusCode == 429 && ok {
-			return nil, fmt.Errorf("Too many requests, retry after: %s seconds", retryAfter)
-		}
-		bodyBytes, err := io.ReadAll(resp.Body)
+		defer closeBody(resp.Body)
+		apiErr := &APIError{StatusCode: resp.StatusCode, RetryAfter: resp.Header.Get("Retry-After")}
+		bodyBytes, err := io.ReadAll(io.LimitReader(resp.Body, c.maxResponseBytes()))
 		if err != nil {
 			return nil, fmt.Errorf("response code %d. unable to read response body. got %w", resp.StatusCode, err)
 		}
-		return nil, fmt.Errorf(string(bodyBytes))
+		apiErr.Body = string(bodyBytes)
+		return nil, apiErr
 	}
 
 	return resp, nil
 }
 
 func (c *Client) Ping() (*PingResponse, error) {
-	var data *PingResponse
-	resp, err := c.apiCall("ping", http.MethodGet)
-	if err != nil {
-		return nil, err
-	}
-	body := resp.Body
-	defer closeBody(body)
-	if err := json.NewDecoder(body).Decode(&data); err != nil {
-		return nil, err
-	}
-	return data, nil
+	return do[struct{}, PingResponse](context.Background(), c, http.MethodGet, "ping", nil)
 }
 
 // GetTableInformation looks up a table by `tableName`.
@@ -141,18 +289,7 @@ func (c *Client) Ping() (*PingResponse, error) {
 // For example, a Snowflake table with a warehouse called `square` and a table
 // called `items.variations` should be referenced as `square.items.variations`.
 func (c *Client) GetTableInformation(tableName string) (*GetTableResponse, error) {
-	var data *GetTableResponse
-	req := fmt.Sprintf("{\"table_name\": \"%s\"}", tableName)
-	resp, err := c.apiCallWithBody("get_table_information", http.MethodGet, req)
-	if err != nil {
-		return nil, err
-	}
-	body := resp.Body
-	defer closeBody(body)
-	if err := json.NewDecoder(body).Decode(&data); err != nil {
-		return nil, err
-	}
-	return data, nil
+	return c.GetTableInformationFromRequest(GetTableInformationRequest{TableName: tableName})
 }
 
 // GetTableInformationFromRequest supports looking up a table via query params
@@ -162,51 +299,20 @@ func (c *Client) GetTableInformation(tableName string) (*GetTableResponse, error
 // there are multiple warehouses with the same name, then you should differentiate
 // via the warehouseID parameter instead.
 func (c *Client) GetTableInformationFromRequest(req GetTableInformationRequest) (*GetTableResponse, error) {
-	var data *GetTableResponse
-	reqJson, err := json.Marshal(req)
-	resp, err := c.apiCallWithBody("get_table_information", http.MethodGet, string(reqJson))
-	if err != nil {
-		return nil, err
-	}
-	body := resp.Body
-	defer closeBody(body)
-	if err := json.NewDecoder(body).Decode(&data); err != nil {
-		return nil, err
-	}
-	return data, nil
+	return c.getTableInformation(context.Background(), req)
+}
+
+func (c *Client) getTableInformation(ctx context.Context, req GetTableInformationRequest) (*GetTableResponse, error) {
+	return do[GetTableInformationRequest, GetTableResponse](ctx, c, http.MethodGet, "get_table_information", &req)
 }
 
 func (c *Client) ConfigureTable(req ConfigureTableRequest) (*ConfigureTableResponse, error) {
-	var data *ConfigureTableResponse
-	reqJson, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := c.apiCallWithBody("configure_table", http.MethodPost, string(reqJson))
-	if err != nil {
-		return nil, err
-	}
-	body := resp.Body
-	defer closeBody(body)
-	if err := json.NewDecoder(body).Decode(&data); err != nil {
-		return nil, err
-	}
-	return data, nil
+	return do[ConfigureTableRequest, ConfigureTableResponse](context.Background(), c, http.MethodPost, "configure_table", &req)
 }
 
 func (c *Client) GetChecks(tableID int) (*GetChecksResponse, error) {
-	var data *GetChecksResponse
-	req := fmt.Sprintf("{\"table_id\": \"%d\"}", tableID)
-	resp, err := c.apiCallWithBody("get_checks_for_table", http.MethodGet, req)
-	if err != nil {
-		return nil, err
-	}
-	body := resp.Body
-	defer closeBody(body)
-	if err := json.NewDecoder(body).Decode(&data); err != nil {
-		return nil, err
-	}
-	return data, nil
+	req := getChecksRequest{TableID: tableID}
+	return do[getChecksRequest, GetChecksResponse](context.Background(), c, http.MethodGet, "get_checks_for_table", &req)
 }
 
 // GetCheckByStaticID Wrapper around GetChecks that additionally filters checks
@@ -266,71 +372,52 @@ func (c *Client) GetCheckByRef(tableID int, ref string) (*Check, error) {
 }
 
 func (c *Client) CreateCheck(req CreateCheckRequest) (*CreateCheckResponse, error) {
-	var data *CreateCheckResponse
-	reqJson, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
+	return do[CreateCheckRequest, CreateCheckResponse](context.Background(), c, http.MethodPost, "create_check", &req)
+}
+
+// checkMatchesRequest Reports whether an existing check has the type and
+// params that `req` would create it with.
+func checkMatchesRequest(check *Check, req CreateCheckRequest) bool {
+	checkType := check.CheckType
+	if checkType == "" {
+		checkType = check.Config.Check
 	}
-	resp, err := c.apiCallWithBody("create_check", http.MethodPost, string(reqJson))
-	if err != nil {
-		return nil, err
+	if checkType != req.CheckType || len(check.Config.Params) != len(req.Params) {
+		return false
 	}
-	body := resp.Body
-	defer closeBody(body)
-	if err := json.NewDecoder(body).Decode(&data); err != nil {
-		return nil, err
+	for key, want := range req.Params {
+		got, ok := check.Config.Params[key]
+		if !ok {
+			return false
+		}
+		if s, isString := got.(string); isString {
+			if s != want {
+				return false
+			}
+			continue
+		}
+		encoded, err := json.Marshal(got) // Params other than strings are JSON encoded
+		if err != nil || string(encoded) != want {
+			return false
+		}
 	}
-	return data, nil
+	return true
 }
 
 func (c *Client) DeleteCheck(req DeleteCheckRequest) (*DeleteCheckResponse, error) {
-	var data *DeleteCheckResponse
-	reqJson, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := c.apiCallWithBody("delete_check", http.MethodPost, string(reqJson))
-	if err != nil {
-		return nil, err
-	}
-	body := resp.Body
-	defer closeBody(body)
-	if err := json.NewDecoder(body).Decode(&data); err != nil {
-		return nil, err
-	}
-	return data, nil
+	return do[DeleteCheckRequest, DeleteCheckResponse](context.Background(), c, http.MethodPost, "delete_check", &req)
 }
 
 func (c *Client) RunChecks(req RunChecksRequest) (*RunChecksResponse, error) {
-        var data *RunChecksResponse
-        reqJson, err := json.Marshal(req)
-        if err != nil {
-                return nil, err
-        }
-        resp, err := c.apiCallWithBody("run_checks", http.MethodPost, string(reqJson))
-        if err != nil {
-                return nil, err
-        }
-        body := resp.Body
-        defer closeBody(body)
-        if err := json.NewDecoder(body).Decode(&data); err != nil {
-                return nil, err
-        }
-        return data, nil
+	return c.runChecks(context.Background(), req)
+}
+
+func (c *Client) runChecks(ctx context.Context, req RunChecksRequest) (*RunChecksResponse, error) {
+	return do[RunChecksRequest, RunChecksResponse](ctx, c, http.MethodPost, "run_checks", &req)
 }
 
 func (c *Client) GetNotificationChannels() (*GetNotificationChannelsResponse, error) {
-	var data *GetNotificationChannelsResponse
-	resp, err := c.apiCall("list_notification_channels", http.MethodGet)
-	if err != nil {
-		return nil, err
-	}
-	body := resp.Body
-	defer closeBody(body)
-	if err := json.NewDecoder(body).Decode(&data); err != nil {
-		return nil, err
-	}
-	return data, nil
+	return do[struct{}, GetNotificationChannelsResponse](context.Background(), c, http.MethodGet, "list_notification_channels", nil)
 }
 
 // GetNotificationChannelWithDescriptionContaining Wrapper around
@@ -372,17 +459,11 @@ func (c *Client) GetNotificationChannelWithDescriptionContaining(
 }
 
 func (c *Client) GetOrganizations() ([]*Organization, error) {
-	var data []*Organization
-	resp, err := c.apiCall("organizations", http.MethodGet)
+	data, err := do[struct{}, []*Organization](context.Background(), c, http.MethodGet, "organizations", nil)
 	if err != nil {
 		return nil, err
 	}
-	body := resp.Body
-	defer closeBody(body)
-	if err := json.NewDecoder(body).Decode(&data); err != nil {
-		return nil, err
-	}
-	return data, nil
+	return *data, nil
 }
 
 // GetOrganizationByName Wrapper around GetOrganizations that looks for an
@@ -413,47 +494,44 @@ func (c *Client) GetOrganizationByName(name string) (*Organization, error) {
 
 // ChangeOrganization API keys have permissions scoped to a given Organization. An API key can only act within the scope
 // of one organization at a time. Call ChangeOrganization to change the Organization the API Key is acting within.
+//
+// The active organization is server-side state shared by every user of the API key. ChangeOrganization waits for
+// in-flight OrgScopedClient calls to finish, but calls made directly on the Client run in whichever organization is
+// active at the time. Use an OrgScopedClient when goroutines sharing a Client target different organizations.
 func (c *Client) ChangeOrganization(orgId int64) (*ChangeOrganizationResponse, error) {
-	var data *ChangeOrganizationResponse
-	req := fmt.Sprintf("{\"id\": \"%d\"}", orgId)
-	resp, err := c.apiCallWithBody("organization", http.MethodPut, req)
-	if err != nil {
-		return nil, err
+	state := c.getState()
+	state.orgMu.Lock()
+	defer state.orgMu.Unlock()
+	return c.changeOrganization(orgId)
+}
+
+// changeOrganization Like ChangeOrganization, but the caller must hold orgMu.
+func (c *Client) changeOrganization(orgId int64) (*ChangeOrganizationResponse, error) {
+	state := c.getState()
+	req := changeOrganizationRequest{ID: orgId}
+	resp, err := do[changeOrganizationRequest, ChangeOrganizationResponse](context.Background(), c, http.MethodPut, "organization", &req)
+	active := int64(0) // Unknown after a failed change
+	if err == nil {
+		active = int64(resp.ID)
 	}
-	body := resp.Body
-	defer closeBody(body)
-	if err := json.NewDecoder(body).Decode(&data); err != nil {
-		return nil, err
+	if c.Cache != nil && (active == 0 || active != state.activeOrg) {
+		c.Cache.Clear() // Cached responses belong to the previous organization
 	}
-	return data, nil
+	state.activeOrg = active
+	return resp, err
 }
 
 func (c *Client) DiscoverNewWarehouseTables(warehouseId int64) (*DiscoverNewWarehouseTablesResponse, error) {
-	var data *DiscoverNewWarehouseTablesResponse
-	resp, err := c.apiCall(fmt.Sprintf("warehouse/%d/refresh/new", warehouseId), http.MethodPost)
-	if err != nil {
-		return nil, err
-	}
-	body := resp.Body
-	defer closeBody(body)
-	if err := json.NewDecoder(body).Decode(&data); err != nil {
-		return nil, err
-	}
-	return data, nil
+	return c.discoverNewWarehouseTables(context.Background(), warehouseId)
+}
+
+func (c *Client) discoverNewWarehouseTables(ctx context.Context, warehouseId int64) (*DiscoverNewWarehouseTablesResponse, error) {
+	endpoint := fmt.Sprintf("warehouse/%d/refresh/new", warehouseId)
+	return do[struct{}, DiscoverNewWarehouseTablesResponse](ctx, c, http.MethodPost, endpoint, nil)
 }
 
 func (c *Client) ListWarehouses() (*ListWarehousesResponse, error) {
-	var data *ListWarehousesResponse
-	resp, err := c.apiCall("list_warehouses", http.MethodGet)
-	if err != nil {
-		return nil, err
-	}
-	body := resp.Body
-	defer closeBody(body)
-	if err := json.NewDecoder(body).Decode(&data); err != nil {
-		return nil, err
-	}
-	return data, nil
+	return do[struct{}, ListWarehousesResponse](context.Background(), c, http.MethodGet, "list_warehouses", nil)
 }
 
 // For debugging
@@ -0,0 +1,293 @@
+package anomalo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultContractCheckTypes The Anomalo check type each column constraint of
+// a data contract is enforced with.
+var DefaultContractCheckTypes = map[string]string{
+	"required":        "NullCheck",
+	"unique":          "UniqueCheck",
+	"accepted_values": "AcceptedValuesCheck",
+}
+
+// Contract A data contract for one table, e.g.
+//
+//	table: wh.sales.orders
+//	owners: [team-payments]
+//	schedule: daily
+//	time_columns: [created_at]
+//	freshness:
+//	  fresh_after: 2h
+//	  notify_after: 3h
+//	columns:
+//	  - name: id
+//	    type: integer
+//	    required: true
+//	    unique: true
+//	  - name: status
+//	    accepted_values: [open, closed]
+//
+// Column types and descriptions are documentation only; they are not
+// enforced.
+type Contract struct {
+	Table          string             `yaml:"table"`
+	Owners         []string           `yaml:"owners"`
+	Schedule       string             `yaml:"schedule"`
+	TimeColumnType string             `yaml:"time_column_type"`
+	TimeColumns    []string           `yaml:"time_columns"`
+	Freshness      *ContractFreshness `yaml:"freshness"`
+	Columns        []ContractColumn   `yaml:"columns"`
+}
+
+// ContractFreshness The freshness SLA of a table.
+type ContractFreshness struct {
+	FreshAfter  string `yaml:"fresh_after"`
+	NotifyAfter string `yaml:"notify_after"`
+}
+
+// ContractColumn A column of a contract and the constraints on its values.
+type ContractColumn struct {
+	Name           string   `yaml:"name"`
+	Type           string   `yaml:"type"`
+	Description    string   `yaml:"description"`
+	Required       bool     `yaml:"required"`
+	Unique         bool     `yaml:"unique"`
+	AcceptedValues []string `yaml:"accepted_values"`
+}
+
+// ParseContract Parses a data contract. Unknown keys are rejected, so typos
+// do not silently drop a clause.
+func ParseContract(data []byte) (*Contract, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var contract Contract
+	if err := decoder.Decode(&contract); err != nil {
+		return nil, fmt.Errorf("unable to parse data contract. %w", err)
+	}
+	return &contract, nil
+}
+
+// LoadContract Reads and parses a data contract file.
+func LoadContract(path string) (*Contract, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	contract, err := ParseContract(contents)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return contract, nil
+}
+
+// ContractCheck A check that enforces one contract clause.
+type ContractCheck struct {
+	// Clause Names the clause, e.g. "columns.id.unique".
+	Clause  string
+	Request CreateCheckRequest
+}
+
+// CompiledContract What it takes to enforce a contract in Anomalo.
+type CompiledContract struct {
+	TableName string
+	// Config The table settings the contract sets. Settings the contract
+	// doesn't mention are unset, so ConfigureTable leaves them unchanged.
+	// TableID must be filled in before use.
+	Config ConfigureTableRequest
+	// Checks Each check has a Ref derived from the table and clause, so
+	// compiling the same contract again gives the same Refs.
+	Checks []ContractCheck
+	// Labels Owner labels to attach to the table. See AddLabelsToTable.
+	Labels []string
+}
+
+// Compile Converts the contract to table settings and checks. `checkTypes`
+// overrides DefaultContractCheckTypes when not nil.
+func (ct *Contract) Compile(checkTypes map[string]string) (*CompiledContract, error) {
+	if ct.Table == "" {
+		return nil, fmt.Errorf("data contract has no table")
+	}
+	if checkTypes == nil {
+		checkTypes = DefaultContractCheckTypes
+	}
+	compiled := &CompiledContract{TableName: ct.Table}
+
+	if ct.Schedule != "" {
+		compiled.Config.CheckCadenceType = Set(ct.Schedule)
+	}
+	if ct.TimeColumnType != "" {
+		compiled.Config.TimeColumnType = Set(ct.TimeColumnType)
+	}
+	if len(ct.TimeColumns) > 0 {
+		compiled.Config.TimeColumns = Set(ct.TimeColumns)
+	}
+	if ct.Freshness != nil {
+		if ct.Freshness.FreshAfter != "" {
+			compiled.Config.FreshAfter = Set(ct.Freshness.FreshAfter)
+		}
+		if ct.Freshness.NotifyAfter != "" {
+			compiled.Config.NotifyAfter = Set(ct.Freshness.NotifyAfter)
+		}
+	}
+	for _, owner := range ct.Owners {
+		compiled.Labels = append(compiled.Labels, "owner:"+owner)
+	}
+
+	seen := map[string]bool{}
+	for i, column := range ct.Columns {
+		if column.Name == "" {
+			return nil, fmt.Errorf("data contract column %d has no name", i)
+		}
+		if seen[column.Name] {
+			return nil, fmt.Errorf("data contract column %s is declared twice", column.Name)
+		}
+		seen[column.Name] = true
+
+		var clauses []string
+		if column.Required {
+			clauses = append(clauses, "required")
+		}
+		if column.Unique {
+			clauses = append(clauses, "unique")
+		}
+		if len(column.AcceptedValues) > 0 {
+			clauses = append(clauses, "accepted_values")
+		}
+		for _, clause := range clauses {
+			checkType, ok := checkTypes[clause]
+			if !ok {
+				return nil, fmt.Errorf("no check type for %s columns", clause)
+			}
+			params := map[string]string{"column_name": column.Name}
+			if clause == "accepted_values" {
+				values, err := json.Marshal(column.AcceptedValues)
+				if err != nil {
+					return nil, err
+				}
+				params["values"] = string(values)
+			}
+			name := fmt.Sprintf("columns.%s.%s", column.Name, clause)
+			compiled.Checks = append(compiled.Checks, ContractCheck{
+				Clause: name,
+				Request: CreateCheckRequest{
+					CheckType: checkType,
+					Params:    params,
+					Ref:       fmt.Sprintf("contract:%s:%s", ct.Table, name),
+				},
+			})
+		}
+	}
+	return compiled, nil
+}
+
+// ContractReport Which clauses of a contract are enforced on a table.
+type ContractReport struct {
+	TableID int
+	// Covered, Changed & Missing Clause names, in contract order. A changed
+	// clause has a check with its Ref, but the check's type or params no
+	// longer match the contract.
+	Covered []string
+	Changed []string
+	Missing []string
+	// MissingLabels Owner labels that are not on the table.
+	MissingLabels []string
+	// ConfigDiff The table settings that differ from the contract.
+	ConfigDiff *TableConfigDiff
+}
+
+// Satisfied Reports whether every clause is enforced.
+func (r *ContractReport) Satisfied() bool {
+	return len(r.Changed) == 0 && len(r.Missing) == 0 && len(r.MissingLabels) == 0 && r.ConfigDiff.Empty()
+}
+
+func (r *ContractReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "table %d: %d clause(s) covered, %d changed, %d missing",
+		r.TableID, len(r.Covered), len(r.Changed), len(r.Missing))
+	for _, clause := range r.Changed {
+		fmt.Fprintf(&b, "\n  changed: %s", clause)
+	}
+	for _, clause := range r.Missing {
+		fmt.Fprintf(&b, "\n  missing: %s", clause)
+	}
+	for _, label := range r.MissingLabels {
+		fmt.Fprintf(&b, "\n  label: %s", label)
+	}
+	for _, change := range r.ConfigDiff.Changes {
+		fmt.Fprintf(&b, "\n  setting: %s: %s -> %s", change.Field, formatDiffValue(change.From), formatDiffValue(change.To))
+	}
+	return b.String()
+}
+
+// VerifyContract Reports which clauses of a compiled contract are not yet
+// enforced on its table. A check clause counts as covered if a check of the
+// same type on the same column has the same params, so checks created by
+// hand are recognized too. A check that only matches the clause's Ref is
+// reported as changed. Owner labels are looked up among the table's labels
+// by name. Nothing is changed.
+func (c *Client) VerifyContract(compiled *CompiledContract) (*ContractReport, error) {
+	table, err := c.GetTableInformation(compiled.TableName)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := c.GetChecks(table.ID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list checks for table %s. %w", compiled.TableName, err)
+	}
+
+	desired := compiled.Config
+	desired.TableID = table.ID
+	report := &ContractReport{TableID: table.ID, ConfigDiff: DiffTableConfig(table.Config, desired)}
+	for _, check := range compiled.Checks {
+		switch contractCheckStatus(existing.Checks, check.Request) {
+		case contractCheckCovered:
+			report.Covered = append(report.Covered, check.Clause)
+		case contractCheckChanged:
+			report.Changed = append(report.Changed, check.Clause)
+		default:
+			report.Missing = append(report.Missing, check.Clause)
+		}
+	}
+
+	labels := map[string]bool{}
+	for _, label := range table.Labels {
+		if label != nil {
+			labels[label.Name] = true
+		}
+	}
+	for _, label := range compiled.Labels {
+		if !labels[label] {
+			report.MissingLabels = append(report.MissingLabels, label)
+		}
+	}
+	return report, nil
+}
+
+type contractCheckState int
+
+const (
+	contractCheckMissing contractCheckState = iota
+	contractCheckChanged
+	contractCheckCovered
+)
+
+func contractCheckStatus(checks []Check, req CreateCheckRequest) contractCheckState {
+	state := contractCheckMissing
+	for i := range checks {
+		if checkMatchesRequest(&checks[i], req) {
+			return contractCheckCovered
+		}
+		if checks[i].Ref == req.Ref {
+			state = contractCheckChanged
+		}
+	}
+	return state
+}
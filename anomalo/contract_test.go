@@ -0,0 +1,143 @@
+package anomalo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testContract = `
+table: wh.sales.orders
+owners: [team-payments]
+schedule: daily
+time_columns: [created_at]
+freshness:
+  fresh_after: 2h
+columns:
+  - name: id
+    type: integer
+    required: true
+    unique: true
+  - name: status
+    accepted_values: [open, closed]
+  - name: note
+    description: free text
+`
+
+func TestCompileContract(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte(testContract), 0o644))
+	contract, err := LoadContract(path)
+	assert.Nil(t, err)
+
+	compiled, err := contract.Compile(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, ConfigureTableRequest{
+		CheckCadenceType: Set("daily"),
+		TimeColumns:      Set([]string{"created_at"}),
+		FreshAfter:       Set("2h"),
+	}, compiled.Config)
+	assert.Equal(t, []string{"owner:team-payments"}, compiled.Labels)
+	assert.Equal(t, []ContractCheck{
+		{Clause: "columns.id.required", Request: CreateCheckRequest{
+			CheckType: "NullCheck",
+			Params:    map[string]string{"column_name": "id"},
+			Ref:       "contract:wh.sales.orders:columns.id.required",
+		}},
+		{Clause: "columns.id.unique", Request: CreateCheckRequest{
+			CheckType: "UniqueCheck",
+			Params:    map[string]string{"column_name": "id"},
+			Ref:       "contract:wh.sales.orders:columns.id.unique",
+		}},
+		{Clause: "columns.status.accepted_values", Request: CreateCheckRequest{
+			CheckType: "AcceptedValuesCheck",
+			Params:    map[string]string{"column_name": "status", "values": `["open","closed"]`},
+			Ref:       "contract:wh.sales.orders:columns.status.accepted_values",
+		}},
+	}, compiled.Checks)
+}
+
+func TestParseContractErrors(t *testing.T) {
+	_, err := ParseContract([]byte("table: t\ncolumns:\n  - name: id\n    requird: true\n"))
+	assert.NotNil(t, err)
+
+	contract, err := ParseContract([]byte("columns:\n  - name: id\n"))
+	assert.Nil(t, err)
+	_, err = contract.Compile(nil)
+	assert.EqualError(t, err, "data contract has no table")
+
+	contract, err = ParseContract([]byte("table: t\ncolumns:\n  - name: id\n  - name: id\n"))
+	assert.Nil(t, err)
+	_, err = contract.Compile(nil)
+	assert.EqualError(t, err, "data contract column id is declared twice")
+}
+
+func TestVerifyContract(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/get_table_information":
+			w.Write([]byte(`{"id": 5, "config": {"check_cadence_type": "daily", "time_columns": ["created_at"], "fresh_after": "6h"},
+				"labels": [{"id": 1, "name": "owner:team-sales"}]}`))
+		case "/api/public/v1/get_checks_for_table":
+			w.Write([]byte(`{"checks": [
+				{"check_id": 1, "check_type": "NullCheck", "ref": "contract:wh.sales.orders:columns.id.required",
+				 "config": {"params": {"column_name": "id"}}},
+				{"check_id": 2, "check_type": "UniqueCheck", "ref": "hand-made", "config": {"params": {"column_name": "id"}}},
+				{"check_id": 3, "check_type": "AcceptedValuesCheck", "ref": "contract:wh.sales.orders:columns.status.accepted_values",
+				 "config": {"params": {"column_name": "status", "values": ["open"]}}}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := &Client{Host: server.URL}
+
+	contract, err := ParseContract([]byte(testContract))
+	assert.Nil(t, err)
+	compiled, err := contract.Compile(nil)
+	assert.Nil(t, err)
+	report, err := client.VerifyContract(compiled)
+	assert.Nil(t, err)
+	assert.False(t, report.Satisfied())
+	assert.Equal(t, []string{"columns.id.required", "columns.id.unique"}, report.Covered)
+	assert.Equal(t, `table 5: 2 clause(s) covered, 1 changed, 0 missing
+  changed: columns.status.accepted_values
+  label: owner:team-payments
+  setting: fresh_after: "6h" -> "2h"`, report.String())
+}
+
+func TestVerifyContractSatisfied(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/get_table_information":
+			w.Write([]byte(`{"id": 5, "config": {"check_cadence_type": "daily", "time_columns": ["created_at"], "fresh_after": "2h"},
+				"labels": [{"id": 1, "name": "owner:team-payments"}]}`))
+		case "/api/public/v1/get_checks_for_table":
+			w.Write([]byte(`{"checks": [
+				{"check_id": 1, "check_type": "NullCheck", "config": {"params": {"column_name": "id"}}},
+				{"check_id": 2, "check_type": "NullCheck", "ref": "contract:wh.sales.orders:columns.id.unique"},
+				{"check_id": 3, "check_type": "UniqueCheck", "config": {"params": {"column_name": "id"}}},
+				{"check_id": 4, "check_type": "AcceptedValuesCheck",
+				 "config": {"params": {"column_name": "status", "values": ["open", "closed"]}}}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := &Client{Host: server.URL}
+
+	contract, err := ParseContract([]byte(testContract))
+	assert.Nil(t, err)
+	compiled, err := contract.Compile(nil)
+	assert.Nil(t, err)
+	report, err := client.VerifyContract(compiled)
+	assert.Nil(t, err)
+	assert.True(t, report.Satisfied(), "a check matching by params covers a clause despite a stale Ref elsewhere")
+	assert.Equal(t, "table 5: 3 clause(s) covered, 0 changed, 0 missing", report.String())
+}
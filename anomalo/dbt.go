@@ -0,0 +1,468 @@
+package anomalo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DbtRefPrefix Starts the Ref of every check created from a dbt manifest, so
+// that imported checks can be told apart from others.
+const DbtRefPrefix = "dbt:"
+
+// DefaultDbtCheckTypes The Anomalo check type each supported dbt test is
+// converted to. "freshness" stands for source freshness, which dbt declares
+// on sources rather than as a test.
+var DefaultDbtCheckTypes = map[string]string{
+	"not_null":        "NullCheck",
+	"unique":          "UniqueCheck",
+	"accepted_values": "AcceptedValuesCheck",
+	"freshness":       "FreshnessCheck",
+}
+
+// DbtManifest The parts of a dbt `manifest.json` used to import checks.
+type DbtManifest struct {
+	Nodes   map[string]DbtNode `json:"nodes"`
+	Sources map[string]DbtNode `json:"sources"`
+}
+
+// DbtNode A model, seed, snapshot, test or source in a dbt manifest.
+type DbtNode struct {
+	UniqueID     string           `json:"unique_id"`
+	ResourceType string           `json:"resource_type"`
+	Database     string           `json:"database"`
+	Schema       string           `json:"schema"`
+	Name         string           `json:"name"`
+	Alias        string           `json:"alias"`
+	Identifier   string           `json:"identifier"`
+	ColumnName   string           `json:"column_name"`
+	AttachedNode string           `json:"attached_node"`
+	DependsOn    DbtDependsOn     `json:"depends_on"`
+	TestMetadata *DbtTestMetadata `json:"test_metadata"`
+	// Freshness & LoadedAtField Only set on sources.
+	Freshness     *DbtFreshness `json:"freshness"`
+	LoadedAtField string        `json:"loaded_at_field"`
+}
+
+type DbtDependsOn struct {
+	Nodes []string `json:"nodes"`
+}
+
+// DbtTestMetadata Identifies a generic test, e.g. `not_null`, and its
+// arguments.
+type DbtTestMetadata struct {
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace"`
+	Kwargs    map[string]interface{} `json:"kwargs"`
+}
+
+type DbtFreshness struct {
+	WarnAfter  DbtFreshnessThreshold `json:"warn_after"`
+	ErrorAfter DbtFreshnessThreshold `json:"error_after"`
+}
+
+type DbtFreshnessThreshold struct {
+	Count  *int   `json:"count"`
+	Period string `json:"period"`
+}
+
+func (t DbtFreshnessThreshold) String() string {
+	if t.Count == nil || t.Period == "" {
+		return ""
+	}
+	return fmt.Sprintf("%d %s", *t.Count, t.Period)
+}
+
+// LoadDbtManifest Reads a dbt manifest, usually `target/manifest.json`.
+func LoadDbtManifest(path string) (*DbtManifest, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var manifest DbtManifest
+	if err := json.Unmarshal(contents, &manifest); err != nil {
+		return nil, fmt.Errorf("unable to parse dbt manifest %s. %w", path, err)
+	}
+	return &manifest, nil
+}
+
+// DbtImportOptions Controls how dbt tests are converted to checks.
+type DbtImportOptions struct {
+	// WarehouseName The Anomalo warehouse the dbt project builds into. Table
+	// names are built as "<warehouse>.<schema>.<table>".
+	WarehouseName string
+	// IncludeDatabase Builds table names as
+	// "<warehouse>.<database>.<schema>.<table>" instead.
+	IncludeDatabase bool
+	// WarehouseID Optionally passed to table lookups, in case warehouse names
+	// are not unique. See PlanDbtImport for how it scopes the stale scan.
+	WarehouseID int
+	// CheckTypes Overrides DefaultDbtCheckTypes.
+	CheckTypes map[string]string
+}
+
+// DbtCheck A check converted from a dbt test.
+type DbtCheck struct {
+	// DbtID The unique ID of the dbt test, or of the source for freshness.
+	DbtID     string
+	TableName string
+	// Request The check to create. TableID is filled in by PlanDbtImport.
+	Request CreateCheckRequest
+}
+
+// DbtSkippedTest A dbt test that could not be converted.
+type DbtSkippedTest struct {
+	DbtID  string
+	Reason string
+}
+
+// DbtImport The checks converted from a dbt manifest, sorted by table and
+// Ref.
+type DbtImport struct {
+	Checks  []DbtCheck
+	Skipped []DbtSkippedTest
+}
+
+// ConvertDbtManifest Converts the supported tests of a dbt manifest to
+// checks: `not_null`, `unique` and `accepted_values` tests, and source
+// freshness. Each check's Ref is derived from the dbt unique ID, so
+// converting the same manifest again gives the same Refs.
+func ConvertDbtManifest(manifest *DbtManifest, opts DbtImportOptions) *DbtImport {
+	checkTypes := opts.CheckTypes
+	if checkTypes == nil {
+		checkTypes = DefaultDbtCheckTypes
+	}
+	result := &DbtImport{}
+
+	for _, id := range sortedKeys(manifest.Nodes) {
+		node := manifest.Nodes[id]
+		if node.ResourceType != "test" {
+			continue
+		}
+		if node.TestMetadata == nil {
+			result.Skipped = append(result.Skipped, DbtSkippedTest{DbtID: id, Reason: "singular tests are not supported"})
+			continue
+		}
+		checkType, ok := checkTypes[node.TestMetadata.Name]
+		if !ok || node.TestMetadata.Name == "freshness" {
+			result.Skipped = append(result.Skipped, DbtSkippedTest{
+				DbtID:  id,
+				Reason: fmt.Sprintf("%s tests are not supported", node.TestMetadata.Name),
+			})
+			continue
+		}
+		target, ok := dbtTestTarget(manifest, node)
+		if !ok {
+			result.Skipped = append(result.Skipped, DbtSkippedTest{DbtID: id, Reason: "tested model not found in manifest"})
+			continue
+		}
+		params, err := dbtTestParams(node)
+		if err != nil {
+			result.Skipped = append(result.Skipped, DbtSkippedTest{DbtID: id, Reason: err.Error()})
+			continue
+		}
+		result.Checks = append(result.Checks, DbtCheck{
+			DbtID:     id,
+			TableName: dbtTableName(target, opts),
+			Request:   CreateCheckRequest{CheckType: checkType, Params: params, Ref: DbtRefPrefix + id},
+		})
+	}
+
+	if checkType, ok := checkTypes["freshness"]; ok {
+		for _, id := range sortedKeys(manifest.Sources) {
+			source := manifest.Sources[id]
+			if source.Freshness == nil || source.LoadedAtField == "" {
+				continue
+			}
+			params := map[string]string{"column_name": source.LoadedAtField}
+			if warn := source.Freshness.WarnAfter.String(); warn != "" {
+				params["warn_after"] = warn
+			}
+			if errorAfter := source.Freshness.ErrorAfter.String(); errorAfter != "" {
+				params["error_after"] = errorAfter
+			}
+			if len(params) == 1 {
+				continue // No thresholds
+			}
+			result.Checks = append(result.Checks, DbtCheck{
+				DbtID:     id,
+				TableName: dbtTableName(source, opts),
+				Request:   CreateCheckRequest{CheckType: checkType, Params: params, Ref: DbtRefPrefix + id + ":freshness"},
+			})
+		}
+	}
+
+	sort.SliceStable(result.Checks, func(i, j int) bool {
+		if result.Checks[i].TableName != result.Checks[j].TableName {
+			return result.Checks[i].TableName < result.Checks[j].TableName
+		}
+		return result.Checks[i].Request.Ref < result.Checks[j].Request.Ref
+	})
+	return result
+}
+
+// dbtTestTarget Finds the model or source a test is attached to.
+func dbtTestTarget(manifest *DbtManifest, test DbtNode) (DbtNode, bool) {
+	ids := test.DependsOn.Nodes
+	if test.AttachedNode != "" {
+		ids = []string{test.AttachedNode} // Set by dbt 1.5 and later
+	}
+	for _, id := range ids {
+		if node, ok := manifest.Nodes[id]; ok && node.ResourceType != "macro" {
+			return node, true
+		}
+		if source, ok := manifest.Sources[id]; ok {
+			return source, true
+		}
+	}
+	return DbtNode{}, false
+}
+
+func dbtTestParams(test DbtNode) (map[string]string, error) {
+	kwargs := test.TestMetadata.Kwargs
+	column := test.ColumnName
+	if column == "" {
+		column, _ = kwargs["column_name"].(string)
+	}
+	if column == "" {
+		return nil, fmt.Errorf("%s test has no column", test.TestMetadata.Name)
+	}
+	params := map[string]string{"column_name": column}
+	if test.TestMetadata.Name == "accepted_values" {
+		values, ok := kwargs["values"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("accepted_values test has no values")
+		}
+		encoded, err := json.Marshal(values)
+		if err != nil {
+			return nil, err
+		}
+		params["values"] = string(encoded)
+	}
+	return params, nil
+}
+
+func dbtTableName(node DbtNode, opts DbtImportOptions) string {
+	relation := node.Name
+	if node.Alias != "" {
+		relation = node.Alias
+	} else if node.Identifier != "" {
+		relation = node.Identifier
+	}
+	parts := []string{opts.WarehouseName}
+	if opts.IncludeDatabase {
+		parts = append(parts, node.Database)
+	}
+	return strings.Join(append(parts, node.Schema, relation), ".")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DbtPlanAction What PlanDbtImport found for one check.
+type DbtPlanAction string
+
+const (
+	// DbtPlanCreate The check does not exist yet.
+	DbtPlanCreate DbtPlanAction = "create"
+	// DbtPlanUnchanged The check exists with the same type and params.
+	DbtPlanUnchanged DbtPlanAction = "unchanged"
+	// DbtPlanChanged The check exists, but its type or params differ from the
+	// manifest.
+	DbtPlanChanged DbtPlanAction = "changed"
+	// DbtPlanStale The check was imported from dbt, but is no longer in the
+	// manifest.
+	DbtPlanStale DbtPlanAction = "stale"
+	// DbtPlanTableNotFound The table could not be found in Anomalo.
+	DbtPlanTableNotFound DbtPlanAction = "table_not_found"
+)
+
+// DbtPlanItem One entry of a DbtPlan. Request is set for checks in the
+// manifest, and Existing for checks that exist in Anomalo.
+type DbtPlanItem struct {
+	Action    DbtPlanAction
+	TableName string
+	TableID   int
+	Request   CreateCheckRequest
+	Existing  *Check
+	Err       error
+}
+
+func (i DbtPlanItem) String() string {
+	ref := i.Request.Ref
+	if i.Existing != nil {
+		ref = i.Existing.Ref
+	}
+	line := fmt.Sprintf("[%s] %s %s", i.Action, i.TableName, ref)
+	if i.Err != nil {
+		line += ": " + i.Err.Error()
+	}
+	return line
+}
+
+// DbtPlan How to bring Anomalo in line with a dbt manifest.
+type DbtPlan struct {
+	Items []DbtPlanItem
+}
+
+func (p *DbtPlan) String() string {
+	lines := make([]string, len(p.Items))
+	for i, item := range p.Items {
+		lines[i] = item.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PlanDbtImport Compares converted dbt checks with the checks that exist in
+// Anomalo, matching them by Ref. Nothing is changed; see ApplyDbtPlan.
+//
+// Stale checks are also looked for on tables that no longer have any tests
+// in the manifest, by listing the tables of opts.WarehouseID, or of the
+// warehouse named opts.WarehouseName. Without either, only the tables in the
+// manifest are checked. A table that cannot be found is planned as
+// DbtPlanTableNotFound; other lookup errors are returned.
+func (c *Client) PlanDbtImport(imported *DbtImport, opts DbtImportOptions) (*DbtPlan, error) {
+	plan := &DbtPlan{}
+	byTable := map[string][]DbtCheck{}
+	var tables []string
+	for _, check := range imported.Checks {
+		if _, ok := byTable[check.TableName]; !ok {
+			tables = append(tables, check.TableName)
+		}
+		byTable[check.TableName] = append(byTable[check.TableName], check)
+	}
+
+	planned := map[int]bool{}
+	for _, tableName := range tables {
+		checks := byTable[tableName]
+		table, err := c.GetTableInformationFromRequest(GetTableInformationRequest{
+			TableName:   tableName,
+			WarehouseID: opts.WarehouseID,
+		})
+		var apiErr *APIError
+		if err != nil && (!errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound) {
+			return nil, fmt.Errorf("unable to look up table %s. %w", tableName, err)
+		}
+		if err != nil {
+			for _, check := range checks {
+				plan.Items = append(plan.Items, DbtPlanItem{
+					Action:    DbtPlanTableNotFound,
+					TableName: tableName,
+					Request:   check.Request,
+					Err:       err,
+				})
+			}
+			continue
+		}
+		planned[table.ID] = true
+		byRef, err := c.dbtChecks(table.ID, tableName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, check := range checks {
+			item := DbtPlanItem{Action: DbtPlanCreate, TableName: tableName, TableID: table.ID, Request: check.Request}
+			item.Request.TableID = table.ID
+			if current, ok := byRef[check.Request.Ref]; ok {
+				item.Existing = current
+				item.Action = DbtPlanUnchanged
+				if !checkMatchesRequest(current, check.Request) {
+					item.Action = DbtPlanChanged
+				}
+				delete(byRef, check.Request.Ref)
+			}
+			plan.Items = append(plan.Items, item)
+		}
+		plan.Items = appendDbtStale(plan.Items, tableName, table.ID, byRef)
+	}
+
+	// Tables whose tests were all removed from the manifest still carry the
+	// checks imported from them
+	warehouseID, err := c.dbtWarehouseID(opts)
+	if err != nil || warehouseID == 0 {
+		return plan, err
+	}
+	listed, err := c.ListTables(ListTablesRequest{WarehouseID: warehouseID})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list tables. %w", err)
+	}
+	for _, table := range listed.Tables {
+		if planned[table.ID] {
+			continue
+		}
+		byRef, err := c.dbtChecks(table.ID, table.FullName)
+		if err != nil {
+			return nil, err
+		}
+		plan.Items = appendDbtStale(plan.Items, table.FullName, table.ID, byRef)
+	}
+	return plan, nil
+}
+
+// dbtWarehouseID Returns opts.WarehouseID, or else the ID of the warehouse
+// named opts.WarehouseName. Returns 0 if neither is set.
+func (c *Client) dbtWarehouseID(opts DbtImportOptions) (int, error) {
+	if opts.WarehouseID != 0 || opts.WarehouseName == "" {
+		return opts.WarehouseID, nil
+	}
+	warehouses, err := c.ListWarehouses()
+	if err != nil {
+		return 0, fmt.Errorf("unable to list warehouses. %w", err)
+	}
+	for _, warehouse := range warehouses.Warehouses {
+		if warehouse.Name == opts.WarehouseName {
+			return warehouse.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("no warehouse is named %q", opts.WarehouseName)
+}
+
+// dbtChecks Returns the checks on a table that were imported from dbt, by Ref.
+func (c *Client) dbtChecks(tableID int, tableName string) (map[string]*Check, error) {
+	existing, err := c.GetChecks(tableID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list checks for table %s. %w", tableName, err)
+	}
+	byRef := map[string]*Check{}
+	for i := range existing.Checks {
+		if strings.HasPrefix(existing.Checks[i].Ref, DbtRefPrefix) {
+			byRef[existing.Checks[i].Ref] = &existing.Checks[i]
+		}
+	}
+	return byRef, nil
+}
+
+func appendDbtStale(items []DbtPlanItem, tableName string, tableID int, byRef map[string]*Check) []DbtPlanItem {
+	for _, ref := range sortedKeys(byRef) {
+		items = append(items, DbtPlanItem{
+			Action:    DbtPlanStale,
+			TableName: tableName,
+			TableID:   tableID,
+			Existing:  byRef[ref],
+		})
+	}
+	return items
+}
+
+// ApplyDbtPlan Creates the checks the plan marks DbtPlanCreate, using
+// BulkCreateChecks. Changed and stale checks are left for the caller to
+// review. Results are in the order of the created items.
+func (c *Client) ApplyDbtPlan(plan *DbtPlan, opts BulkOptions) ([]BulkResult[CreateCheckResponse], error) {
+	var reqs []CreateCheckRequest
+	for _, item := range plan.Items {
+		if item.Action == DbtPlanCreate {
+			reqs = append(reqs, item.Request)
+		}
+	}
+	return c.BulkCreateChecks(reqs, opts)
+}
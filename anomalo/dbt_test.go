@@ -0,0 +1,159 @@
+package anomalo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const dbtManifest = `{
+	"nodes": {
+		"model.shop.orders": {
+			"unique_id": "model.shop.orders", "resource_type": "model",
+			"database": "analytics", "schema": "sales", "name": "orders", "alias": "fct_orders"
+		},
+		"test.shop.not_null_orders_id.1a": {
+			"resource_type": "test", "column_name": "id", "attached_node": "model.shop.orders",
+			"test_metadata": {"name": "not_null", "kwargs": {"column_name": "id", "model": "{{ ref('orders') }}"}}
+		},
+		"test.shop.accepted_values_orders_status.2b": {
+			"resource_type": "test", "depends_on": {"nodes": ["macro.dbt.test_accepted_values", "model.shop.orders"]},
+			"test_metadata": {"name": "accepted_values", "kwargs": {"column_name": "status", "values": ["open", "closed"]}}
+		},
+		"test.shop.relationships_orders_customer.3c": {
+			"resource_type": "test", "attached_node": "model.shop.orders",
+			"test_metadata": {"name": "relationships", "kwargs": {"column_name": "customer_id"}}
+		},
+		"test.shop.assert_positive_totals": {"resource_type": "test", "attached_node": "model.shop.orders"}
+	},
+	"sources": {
+		"source.shop.raw.payments": {
+			"unique_id": "source.shop.raw.payments", "resource_type": "source",
+			"database": "raw", "schema": "stripe", "name": "payments", "identifier": "payments_v2",
+			"loaded_at_field": "_loaded_at",
+			"freshness": {"warn_after": {"count": 6, "period": "hour"}, "error_after": {"count": 1, "period": "day"}}
+		}
+	}
+}`
+
+func loadTestDbtImport(t *testing.T) *DbtImport {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	assert.Nil(t, os.WriteFile(path, []byte(dbtManifest), 0o644))
+	manifest, err := LoadDbtManifest(path)
+	assert.Nil(t, err)
+	return ConvertDbtManifest(manifest, DbtImportOptions{WarehouseName: "wh"})
+}
+
+func TestConvertDbtManifest(t *testing.T) {
+	imported := loadTestDbtImport(t)
+	assert.Equal(t, []DbtCheck{
+		{
+			DbtID:     "test.shop.accepted_values_orders_status.2b",
+			TableName: "wh.sales.fct_orders",
+			Request: CreateCheckRequest{
+				CheckType: "AcceptedValuesCheck",
+				Params:    map[string]string{"column_name": "status", "values": `["open","closed"]`},
+				Ref:       "dbt:test.shop.accepted_values_orders_status.2b",
+			},
+		},
+		{
+			DbtID:     "test.shop.not_null_orders_id.1a",
+			TableName: "wh.sales.fct_orders",
+			Request: CreateCheckRequest{
+				CheckType: "NullCheck",
+				Params:    map[string]string{"column_name": "id"},
+				Ref:       "dbt:test.shop.not_null_orders_id.1a",
+			},
+		},
+		{
+			DbtID:     "source.shop.raw.payments",
+			TableName: "wh.stripe.payments_v2",
+			Request: CreateCheckRequest{
+				CheckType: "FreshnessCheck",
+				Params:    map[string]string{"column_name": "_loaded_at", "warn_after": "6 hour", "error_after": "1 day"},
+				Ref:       "dbt:source.shop.raw.payments:freshness",
+			},
+		},
+	}, imported.Checks)
+	assert.Equal(t, []DbtSkippedTest{
+		{DbtID: "test.shop.assert_positive_totals", Reason: "singular tests are not supported"},
+		{DbtID: "test.shop.relationships_orders_customer.3c", Reason: "relationships tests are not supported"},
+	}, imported.Skipped)
+}
+
+func TestPlanDbtImport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/get_table_information":
+			if r.URL.Query().Get("table_name") != "wh.sales.fct_orders" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`table not found`))
+				return
+			}
+			w.Write([]byte(`{"id": 5}`))
+		case "/api/public/v1/list_warehouses":
+			w.Write([]byte(`{"warehouses": [{"id": 2, "name": "other"}, {"id": 1, "name": "wh"}]}`))
+		case "/api/public/v1/list_tables":
+			assert.Equal(t, "warehouse_id=1", r.URL.RawQuery)
+			w.Write([]byte(`{"tables": [
+				{"id": 5, "full_name": "wh.sales.fct_orders"},
+				{"id": 6, "full_name": "wh.sales.dim_retired"},
+				{"id": 7, "full_name": "wh.sales.hand_made"}
+			]}`))
+		case "/api/public/v1/get_checks_for_table":
+			switch r.URL.Query().Get("table_id") {
+			case "5":
+				w.Write([]byte(`{"checks": [
+					{"check_id": 1, "check_type": "NullCheck", "ref": "dbt:test.shop.not_null_orders_id.1a",
+					 "config": {"params": {"column_name": "id"}}},
+					{"check_id": 2, "check_type": "AcceptedValuesCheck", "ref": "dbt:test.shop.accepted_values_orders_status.2b",
+					 "config": {"params": {"column_name": "status", "values": ["open"]}}},
+					{"check_id": 3, "check_type": "NullCheck", "ref": "dbt:test.shop.not_null_orders_old.9z"},
+					{"check_id": 4, "check_type": "NullCheck", "ref": "hand-made"}
+				]}`))
+			case "6":
+				w.Write([]byte(`{"checks": [{"check_id": 5, "check_type": "NullCheck", "ref": "dbt:test.shop.not_null_retired_id.4d"}]}`))
+			default:
+				w.Write([]byte(`{"checks": [{"check_id": 6, "check_type": "NullCheck", "ref": "hand-made"}]}`))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := &Client{Host: server.URL}
+
+	plan, err := client.PlanDbtImport(loadTestDbtImport(t), DbtImportOptions{WarehouseName: "wh"})
+	assert.Nil(t, err)
+	assert.Equal(t, `[changed] wh.sales.fct_orders dbt:test.shop.accepted_values_orders_status.2b
+[unchanged] wh.sales.fct_orders dbt:test.shop.not_null_orders_id.1a
+[stale] wh.sales.fct_orders dbt:test.shop.not_null_orders_old.9z
+[table_not_found] wh.stripe.payments_v2 dbt:source.shop.raw.payments:freshness: table not found
+[stale] wh.sales.dim_retired dbt:test.shop.not_null_retired_id.4d`, plan.String())
+	assert.Equal(t, 5, plan.Items[0].Request.TableID)
+	assert.Equal(t, 6, plan.Items[len(plan.Items)-1].TableID)
+
+	// Without a warehouse, only the manifest's tables are checked
+	plan, err = client.PlanDbtImport(loadTestDbtImport(t), DbtImportOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, plan.Items, 4)
+
+	_, err = client.PlanDbtImport(loadTestDbtImport(t), DbtImportOptions{WarehouseName: "missing"})
+	assert.EqualError(t, err, `no warehouse is named "missing"`)
+}
+
+func TestPlanDbtImportLookupError(t *testing.T) {
+	server := setupServer(t, "get_table_information?table_name=wh.sales.fct_orders", `forbidden`, http.StatusForbidden)
+	defer server.Close()
+	client := &Client{Host: server.URL}
+
+	_, err := client.PlanDbtImport(loadTestDbtImport(t), DbtImportOptions{})
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "unable to look up table wh.sales.fct_orders. forbidden", err.Error())
+}
@@ -0,0 +1,367 @@
+package anomalo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Response types keep fields that this client does not know about in their
+// Extra map, so callers can read attributes that Anomalo adds before the
+// client catches up. With Client.DecodeMode set to DecodeStrict, any unknown
+// field fails the call instead, wherever it is nested.
+//
+// Every response type has an Extra map, which makes it incomparable with ==.
+// Small value types nested in responses, such as Label, UserRef, Interval and
+// Organization, have none so that they stay comparable; unknown fields within
+// them are dropped, but are still reported by DecodeStrict.
+
+// extraField The Go field that a JSON key decodes into.
+type extraField struct {
+	index []int
+	depth int
+}
+
+// extraFields How unmarshalWithExtra decodes one struct type.
+type extraFields struct {
+	exact map[string]extraField
+	// folded Lower-cased names. encoding/json matches field names
+	// case-insensitively when there is no exact match, so we do too.
+	folded map[string]extraField
+	extra  []int
+}
+
+func (f *extraFields) lookup(key string) (extraField, bool) {
+	if field, ok := f.exact[key]; ok {
+		return field, true
+	}
+	field, ok := f.folded[strings.ToLower(key)]
+	return field, ok
+}
+
+var extraFieldsCache sync.Map // reflect.Type -> *extraFields
+
+var rawMessagesType = reflect.TypeOf(map[string]json.RawMessage(nil))
+
+// fieldsWithExtra Returns the JSON fields of struct type `t`, including the
+// fields promoted from embedded structs, and the index of its Extra field.
+func fieldsWithExtra(t reflect.Type) *extraFields {
+	if cached, ok := extraFieldsCache.Load(t); ok {
+		return cached.(*extraFields)
+	}
+	fields := &extraFields{exact: map[string]extraField{}, folded: map[string]extraField{}}
+	addJSONFields(fields, t, nil)
+	if extra, ok := t.FieldByName("Extra"); ok && extra.Type == rawMessagesType {
+		fields.extra = extra.Index
+	}
+	extraFieldsCache.Store(t, fields)
+	return fields
+}
+
+// addJSONFields Adds the fields of `t` to `fields`. As in encoding/json, a
+// field of an embedded struct is hidden by a field of the same name at a
+// shallower depth.
+func addJSONFields(fields *extraFields, t reflect.Type, index []int) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && (field.IsExported() || field.Type.Kind() != reflect.Pointer) {
+				addJSONFields(fields, embedded, fieldIndex)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		entry := extraField{index: fieldIndex, depth: len(index)}
+		if existing, ok := fields.exact[name]; !ok || entry.depth < existing.depth {
+			fields.exact[name] = entry
+		}
+		folded := strings.ToLower(name)
+		if existing, ok := fields.folded[folded]; !ok || entry.depth < existing.depth {
+			fields.folded[folded] = entry
+		}
+	}
+}
+
+// fieldByIndex Returns the field of `v` at `index`, allocating any nil
+// embedded struct pointers on the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// unmarshalWithExtra Decodes the JSON object `data` into `v`, a pointer to a
+// struct with an Extra field, and stores every field that `v` does not
+// declare in Extra. Extra is left nil when there are no unknown fields.
+//
+// The object is read once: each known field's value is decoded straight into
+// its Go field, so nested types that also keep Extra don't parse their
+// subtree again.
+func unmarshalWithExtra(data []byte, v interface{}) error {
+	target := reflect.ValueOf(v).Elem()
+	fields := fieldsWithExtra(target.Type())
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return &json.UnmarshalTypeError{Value: jsonKind(token), Type: target.Type()}
+	}
+
+	var extra map[string]json.RawMessage
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key := token.(string)
+		field, ok := fields.lookup(key)
+		if !ok {
+			var value json.RawMessage
+			if err := decoder.Decode(&value); err != nil {
+				return err
+			}
+			if extra == nil {
+				extra = map[string]json.RawMessage{}
+			}
+			extra[key] = value
+			continue
+		}
+		if err := decoder.Decode(fieldByIndex(target, field.index).Addr().Interface()); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	if fields.extra != nil {
+		target.FieldByIndex(fields.extra).Set(reflect.ValueOf(extra))
+	}
+	return nil
+}
+
+// jsonKind Describes a JSON token the way json.UnmarshalTypeError does.
+func jsonKind(token json.Token) string {
+	switch token.(type) {
+	case json.Delim:
+		return "array"
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	default:
+		return "number"
+	}
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// unknownFields Returns the path of every field in the JSON document `data`
+// that type `t` does not declare, e.g. `checks[0].config.new_field`, sorted.
+// The document is walked alongside the type rather than the decoded value, so
+// unknown fields within types that have no Extra map are found too.
+func unknownFields(data []byte, t reflect.Type) ([]string, error) {
+	var paths []string
+	if err := collectUnknownFields(data, t, "", &paths); err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func collectUnknownFields(data []byte, t reflect.Type, path string, paths *[]string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return nil // Encoded as a string
+		}
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return err
+		}
+		for i, elem := range elems {
+			if err := collectUnknownFields(elem, t.Elem(), fmt.Sprintf("%s[%d]", path, i), paths); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		for key, value := range values {
+			if err := collectUnknownFields(value, t.Elem(), joinFieldPath(path, key), paths); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		fields := fieldsWithExtra(t)
+		// Types that decode themselves, such as time.Time and Optional, are
+		// not walked into, except the ones that keep an Extra map
+		if fields.extra == nil && reflect.PointerTo(t).Implements(unmarshalerType) {
+			return nil
+		}
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		for key, value := range values {
+			field, ok := fields.lookup(key)
+			if !ok {
+				*paths = append(*paths, joinFieldPath(path, key))
+				continue
+			}
+			fieldType := t.FieldByIndex(field.index).Type
+			if err := collectUnknownFields(value, fieldType, joinFieldPath(path, key), paths); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func joinFieldPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// UnmarshalJSON methods that keep unknown response fields in Extra. See
+// unmarshalWithExtra.
+
+func (r *TableConfig) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *TableRecentStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *GetTableResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *ConfigureTableResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *CheckConfig) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *Check) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *GetChecksResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *ListLabelsResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *LabelsResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *CheckRun) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *RunChecksResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *ListIntervalsResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *GetCheckRunsResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *GetNotificationChannelsResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *GetOrganizationsResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *Warehouse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *ListWarehousesResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *ListTablesResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *PingResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *CreateCheckResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *DeleteCheckResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *DeleteLabelResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *DiscoverNewWarehouseTablesResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *ChangeOrganizationResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func (r *Table) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
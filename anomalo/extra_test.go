@@ -0,0 +1,143 @@
+package anomalo
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const checksWithNewFields = `{
+	"checks": [{"check_id": 1, "new_top": true, "config": {"check": "NullCheck", "new_nested": "x"}}],
+	"next_page": "abc"
+}`
+
+func TestUnknownFieldsAreKept(t *testing.T) {
+	var resp GetChecksResponse
+	assert.Nil(t, json.Unmarshal([]byte(checksWithNewFields), &resp))
+
+	assert.Equal(t, json.RawMessage(`"abc"`), resp.Extra["next_page"])
+	assert.Equal(t, 1, resp.Checks[0].CheckID)
+	assert.Equal(t, json.RawMessage(`true`), resp.Checks[0].Extra["new_top"])
+	assert.Equal(t, "NullCheck", resp.Checks[0].Config.Check)
+	assert.Equal(t, json.RawMessage(`"x"`), resp.Checks[0].Config.Extra["new_nested"])
+	assert.Equal(t, []string{"checks[0].config.new_nested", "checks[0].new_top", "next_page"}, mustUnknownFields(t, checksWithNewFields, &resp))
+}
+
+type embeddedFields struct {
+	Inner string `json:"inner"`
+	Outer string `json:"outer"`
+}
+
+type withEmbedded struct {
+	embeddedFields
+	*CheckMetadata
+	Outer string `json:"outer"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (r *withEmbedded) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r)
+}
+
+func TestUnknownFieldsWithEmbeddedStructs(t *testing.T) {
+	var resp withEmbedded
+	assert.Nil(t, json.Unmarshal([]byte(`{"inner": "a", "outer": "b", "Check_Type": "NullCheck", "new": 1}`), &resp))
+	assert.Equal(t, "a", resp.Inner)
+	assert.Equal(t, "b", resp.Outer)
+	assert.Equal(t, "", resp.embeddedFields.Outer, "the shallower field wins")
+	assert.Equal(t, "NullCheck", resp.CheckType)
+	assert.Equal(t, map[string]json.RawMessage{"new": json.RawMessage(`1`)}, resp.Extra)
+	assert.Equal(t, []string{"new"}, mustUnknownFields(t, `{"inner": "a", "Check_Type": "x", "new": 1}`, &resp))
+}
+
+func TestUnknownFieldsDecodeErrors(t *testing.T) {
+	var resp GetChecksResponse
+	assert.NotNil(t, json.Unmarshal([]byte(`[]`), &resp))
+	assert.EqualError(t, json.Unmarshal([]byte(`{"checks": [{"check_id": "x"}]}`), &resp),
+		"checks: check_id: json: cannot unmarshal string into Go value of type int")
+
+	resp.Extra = map[string]json.RawMessage{"old": nil}
+	assert.Nil(t, json.Unmarshal([]byte(`null`), &resp))
+	assert.Nil(t, json.Unmarshal([]byte(`{}`), &resp))
+	assert.Nil(t, resp.Extra)
+}
+
+func mustUnknownFields(t *testing.T, data string, v interface{}) []string {
+	unknown, err := unknownFields([]byte(data), reflect.TypeOf(v))
+	assert.Nil(t, err)
+	return unknown
+}
+
+func TestSmallValueTypesAreComparable(t *testing.T) {
+	for _, v := range []interface{}{
+		Label{}, UserRef{}, WarehouseRef{}, CheckMetadata{}, Interval{}, CheckRunResults{},
+		NotificationChannel{}, Organization{},
+	} {
+		assert.True(t, reflect.TypeOf(v).Comparable(), reflect.TypeOf(v).Name())
+	}
+}
+
+func TestEveryResponseKeepsUnknownFields(t *testing.T) {
+	for _, v := range []interface{}{
+		&PingResponse{}, &GetTableResponse{}, &ConfigureTableResponse{}, &GetChecksResponse{},
+		&CreateCheckResponse{}, &DeleteCheckResponse{}, &ListLabelsResponse{}, &DeleteLabelResponse{},
+		&LabelsResponse{}, &RunChecksResponse{}, &ListIntervalsResponse{}, &GetCheckRunsResponse{}, &CheckRun{},
+		&GetNotificationChannelsResponse{}, &GetOrganizationsResponse{}, &ChangeOrganizationResponse{},
+		&DiscoverNewWarehouseTablesResponse{}, &Warehouse{}, &ListWarehousesResponse{}, &Table{},
+		&ListTablesResponse{},
+	} {
+		name := reflect.TypeOf(v).Elem().Name()
+		assert.Nil(t, json.Unmarshal([]byte(`{"brand_new": 1}`), v), name)
+		extra := reflect.ValueOf(v).Elem().FieldByName("Extra").Interface()
+		assert.Equal(t, map[string]json.RawMessage{"brand_new": json.RawMessage(`1`)}, extra, name)
+	}
+}
+
+func TestStrictDecodingReportsUnknownFieldsInValueTypes(t *testing.T) {
+	body := `{"check_runs": [{
+		"check_id": 1,
+		"CreatedBy": {"id": 1, "new_user": true},
+		"labels": [{"id": 2, "new_label": true}],
+		"results": {"success": true, "new_result": 1},
+		"run_config": {"_metadata": {"new_meta": 1}, "params": {"anything": {"goes": 1}}}
+	}]}`
+	assert.Equal(t, []string{
+		"check_runs[0].CreatedBy.new_user",
+		"check_runs[0].labels[0].new_label",
+		"check_runs[0].results.new_result",
+		"check_runs[0].run_config._metadata.new_meta",
+	}, mustUnknownFields(t, body, &GetCheckRunsResponse{}))
+
+	server := setupServer(t, "list_tables", `{"tables": [{"id": 1, "new_table_field": 2}]}`, http.StatusOK)
+	defer server.Close()
+	client := &Client{Host: server.URL, DecodeMode: DecodeStrict}
+	_, err := client.ListTables(ListTablesRequest{})
+	assert.EqualError(t, err, "list_tables: decoding response: unknown field(s) tables[0].new_table_field")
+}
+
+func TestConfigureTableResponseKeepsConfig(t *testing.T) {
+	var resp ConfigureTableResponse
+	assert.Nil(t, json.Unmarshal([]byte(`{"id": 1, "config": {"check_cadence_type": "daily", "brand_new": 2}}`), &resp))
+	assert.Equal(t, "daily", resp.Config.CheckCadenceType)
+	assert.Equal(t, json.RawMessage(`2`), resp.Config.Extra["brand_new"])
+}
+
+func TestStrictDecodingReportsNestedUnknownFields(t *testing.T) {
+	server := setupServer(t, "get_checks_for_table?table_id=1", checksWithNewFields, http.StatusOK)
+	defer server.Close()
+
+	client := &Client{Host: server.URL, DecodeMode: DecodeStrict}
+	_, err := client.GetChecks(1)
+	assert.NotNil(t, err)
+	assert.Equal(t, "get_checks_for_table: decoding response: unknown field(s) "+
+		"checks[0].config.new_nested, checks[0].new_top, next_page", err.Error())
+
+	client.DecodeMode = DecodeLenient
+	resp, err := client.GetChecks(1)
+	assert.Nil(t, err)
+	assert.Len(t, resp.Extra, 1)
+}
@@ -0,0 +1,133 @@
+package anomalo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultTableHealthPollInterval How often WaitForTableHealthy checks on a
+	// table when no interval is given.
+	DefaultTableHealthPollInterval = time.Minute
+	// DefaultTableHealthTimeout How long WaitForTableHealthy waits when no
+	// timeout is given.
+	DefaultTableHealthTimeout = 6 * time.Hour
+)
+
+// WaitForTableHealthyOptions Controls WaitForTableHealthy.
+type WaitForTableHealthyOptions struct {
+	// TargetTime Selects the interval to wait for: the one whose time period
+	// covers TargetTime. Defaults to now.
+	TargetTime   time.Time
+	PollInterval time.Duration
+	Timeout      time.Duration
+	// TriggerRun If set and checks have not run on the target interval yet,
+	// RunChecks is called once on that interval with RespectDataFreshnessGate,
+	// so the checks run as soon as the data arrives. Nothing is triggered
+	// until Anomalo has created the interval.
+	TriggerRun bool
+}
+
+// TableUnhealthyError Returned by WaitForTableHealthy when the target interval
+// finished without passing.
+type TableUnhealthyError struct {
+	TableID  int
+	Interval Interval
+	// FailingRuns The check runs of the interval that failed or errored.
+	FailingRuns []CheckRun
+}
+
+func (e *TableUnhealthyError) Error() string {
+	return fmt.Sprintf("table %d interval %d finished with status %q: %d check(s) failed",
+		e.TableID, e.Interval.IntervalID, e.Interval.Status, len(e.FailingRuns))
+}
+
+// WaitForTableHealthy Blocks until Anomalo has validated a table for a point
+// in time, e.g. before starting a job that reads the table.
+//
+// It polls the table's recent intervals until the one covering
+// opts.TargetTime passes, and returns that interval. If the interval fails,
+// errors or is skipped, a *TableUnhealthyError holding the failing check runs
+// is returned right away. `ctx` and opts.Timeout bound the whole wait,
+// including requests in flight. If either ends first, the context's error is
+// returned. Every poll skips Client.Cache.
+func (c *Client) WaitForTableHealthy(
+	ctx context.Context,
+	tableRef GetTableInformationRequest,
+	opts WaitForTableHealthyOptions,
+) (*Interval, error) {
+	target := opts.TargetTime
+	if target.IsZero() {
+		target = time.Now()
+	}
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultTableHealthPollInterval
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTableHealthTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ctx = withoutCachedResponses(ctx) // Always poll fresh results
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	triggered := !opts.TriggerRun
+	for {
+		table, err := c.getTableInformation(ctx, tableRef)
+		if err != nil {
+			return nil, err
+		}
+		current := coveringInterval(table.RecentStatus.RecentIntervals, target)
+
+		switch {
+		case current != nil && current.Status == RunStatusPass:
+			return current, nil
+		case current != nil && (current.Status == RunStatusFail || current.Status == RunStatusError ||
+			current.Status == RunStatusSkipped):
+			return nil, c.tableUnhealthy(ctx, table.ID, *current)
+		case !triggered && current != nil && current.LatestRunChecksJobID == "":
+			req := RunChecksRequest{TableID: table.ID, IntervalID: current.IntervalID, RespectDataFreshnessGate: true}
+			if _, err := c.runChecks(ctx, req); err != nil {
+				return nil, fmt.Errorf("unable to run checks on table %d. %w", table.ID, err)
+			}
+			triggered = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for table %d to be healthy. %w", table.ID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func coveringInterval(intervals []Interval, t time.Time) *Interval {
+	for i := range intervals {
+		if intervals[i].Covers(t) {
+			return &intervals[i]
+		}
+	}
+	return nil
+}
+
+func (c *Client) tableUnhealthy(ctx context.Context, tableID int, interval Interval) error {
+	unhealthy := &TableUnhealthyError{TableID: tableID, Interval: interval}
+	if interval.Status == RunStatusSkipped {
+		return unhealthy
+	}
+	runs, err := c.getCheckRuns(ctx, GetCheckRunsRequest{TableID: tableID, IntervalID: interval.IntervalID})
+	if err != nil {
+		return fmt.Errorf("table %d interval %d finished with status %q. unable to fetch its check runs. %w",
+			tableID, interval.IntervalID, interval.Status, err)
+	}
+	for _, run := range runs.CheckRuns {
+		if run.Failed() {
+			unhealthy.FailingRuns = append(unhealthy.FailingRuns, run)
+		}
+	}
+	return unhealthy
+}
@@ -0,0 +1,159 @@
+package anomalo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeHealthServer Serves table 5, whose interval covering 2023-01-01 has
+// each of `statuses` in turn, repeating the last one. The status
+// intervalNotCreated leaves the interval out.
+const intervalNotCreated = "not created"
+
+func newFakeHealthServer(t *testing.T, statuses []string, runs *[]RunChecksRequest) *httptest.Server {
+	var mu sync.Mutex
+	polls := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/public/v1/get_table_information":
+			status := statuses[len(statuses)-1]
+			if polls < len(statuses) {
+				status = statuses[polls]
+			}
+			polls++
+			jobID := ""
+			if len(*runs) > 0 {
+				jobID = "job"
+			}
+			target := fmt.Sprintf(`,
+				{"interval_id": 7, "status": %q, "latest_run_checks_job_id": %q,
+				 "time_period_start": "2023-01-01T00:00:00Z", "time_period_end": "2023-01-02T00:00:00Z"}`, status, jobID)
+			if status == intervalNotCreated {
+				target = ""
+			}
+			fmt.Fprintf(w, `{"id": 5, "recent_status": {"recent_intervals": [
+				{"interval_id": 6, "status": "pass", "time_period_start": "2022-12-31T00:00:00Z", "time_period_end": "2023-01-01T00:00:00Z"}%s
+			]}}`, target)
+		case "/api/public/v1/run_checks":
+			body, _ := io.ReadAll(r.Body)
+			var req RunChecksRequest
+			assert.Nil(t, json.Unmarshal(body, &req))
+			*runs = append(*runs, req)
+			w.Write([]byte(`{"run_checks_job_id": "job"}`))
+		case "/api/public/v1/get_check_runs":
+			assert.Equal(t, "interval_id=7&table_id=5", r.URL.RawQuery)
+			w.Write([]byte(`{"check_runs": [
+				{"check_id": 1, "results": {"success": true}},
+				{"check_id": 2, "status": "fail", "results": {"success": false}},
+				{"check_id": 3, "status": "skipped", "results": {"success": false}}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+var healthOptions = WaitForTableHealthyOptions{
+	TargetTime:   time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+	PollInterval: time.Millisecond,
+	TriggerRun:   true,
+}
+
+func TestWaitForTableHealthy(t *testing.T) {
+	var runs []RunChecksRequest
+	server := newFakeHealthServer(t, []string{RunStatusPending, RunStatusPending, RunStatusPass}, &runs)
+	defer server.Close()
+	client := &Client{Host: server.URL, Cache: NewResponseCache(time.Hour)}
+
+	interval, err := client.WaitForTableHealthy(context.Background(), GetTableInformationRequest{TableID: 5}, healthOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, interval.IntervalID)
+	assert.Equal(t, []RunChecksRequest{{TableID: 5, IntervalID: 7, RespectDataFreshnessGate: true}}, runs)
+}
+
+func TestWaitForTableHealthyWaitsForIntervalBeforeTriggering(t *testing.T) {
+	var runs []RunChecksRequest
+	server := newFakeHealthServer(t, []string{intervalNotCreated, intervalNotCreated, RunStatusPending, RunStatusPass}, &runs)
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	interval, err := client.WaitForTableHealthy(context.Background(), GetTableInformationRequest{TableID: 5}, healthOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, interval.IntervalID)
+	assert.Equal(t, []RunChecksRequest{{TableID: 5, IntervalID: 7, RespectDataFreshnessGate: true}}, runs)
+}
+
+func TestWaitForTableHealthySkipsCache(t *testing.T) {
+	var runs []RunChecksRequest
+	server := newFakeHealthServer(t, []string{RunStatusFail, RunStatusPass}, &runs)
+	defer server.Close()
+
+	client := &Client{Host: server.URL, Cache: NewResponseCache(time.Hour)}
+	ref := GetTableInformationRequest{TableName: "wh.sales.orders"}
+	_, err := client.GetTableInformationFromRequest(ref) // Caches the failed interval
+	assert.Nil(t, err)
+
+	interval, err := client.WaitForTableHealthy(context.Background(), ref, WaitForTableHealthyOptions{
+		TargetTime: healthOptions.TargetTime, PollInterval: time.Millisecond,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, RunStatusPass, interval.Status)
+
+	table, err := client.GetTableInformationFromRequest(ref)
+	assert.Nil(t, err)
+	assert.Equal(t, RunStatusPass, table.RecentStatus.RecentIntervals[1].Status, "the fresh poll replaces the cached response")
+}
+
+func TestWaitForTableHealthyFailsFast(t *testing.T) {
+	var runs []RunChecksRequest
+	server := newFakeHealthServer(t, []string{RunStatusFail}, &runs)
+	defer server.Close()
+	client := &Client{Host: server.URL}
+
+	_, err := client.WaitForTableHealthy(context.Background(), GetTableInformationRequest{TableID: 5}, healthOptions)
+	var unhealthy *TableUnhealthyError
+	assert.True(t, errors.As(err, &unhealthy))
+	assert.Equal(t, 7, unhealthy.Interval.IntervalID)
+	assert.Len(t, unhealthy.FailingRuns, 1)
+	assert.Equal(t, 2, unhealthy.FailingRuns[0].CheckID)
+	assert.Equal(t, `table 5 interval 7 finished with status "fail": 1 check(s) failed`, err.Error())
+}
+
+func TestWaitForTableHealthyTimeout(t *testing.T) {
+	var runs []RunChecksRequest
+	server := newFakeHealthServer(t, []string{RunStatusPending}, &runs)
+	defer server.Close()
+	client := &Client{Host: server.URL}
+
+	opts := healthOptions
+	opts.TriggerRun = false
+	opts.Timeout = 20 * time.Millisecond
+	_, err := client.WaitForTableHealthy(context.Background(), GetTableInformationRequest{TableID: 5}, opts)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Empty(t, runs)
+}
+
+func TestWaitForTableHealthyTimeoutCancelsRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done() // Only returns once the client gives up
+	}))
+	defer server.Close()
+	client := &Client{Host: server.URL}
+
+	opts := healthOptions
+	opts.Timeout = 20 * time.Millisecond
+	_, err := client.WaitForTableHealthy(context.Background(), GetTableInformationRequest{TableID: 5}, opts)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
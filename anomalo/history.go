@@ -0,0 +1,55 @@
+package anomalo
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ListIntervals Lists a table's check intervals over a date range, including
+// intervals older than those in GetTableResponse.RecentStatus.
+func (c *Client) ListIntervals(req ListIntervalsRequest) (*ListIntervalsResponse, error) {
+	return c.listIntervals(context.Background(), req)
+}
+
+func (c *Client) listIntervals(ctx context.Context, req ListIntervalsRequest) (*ListIntervalsResponse, error) {
+	return do[ListIntervalsRequest, ListIntervalsResponse](ctx, c, http.MethodGet, "get_table_intervals", &req)
+}
+
+// GetCheckRuns Fetches the check runs for one interval of a table, optionally
+// restricted to the checks in CheckIDs.
+func (c *Client) GetCheckRuns(req GetCheckRunsRequest) (*GetCheckRunsResponse, error) {
+	return c.getCheckRuns(context.Background(), req)
+}
+
+func (c *Client) getCheckRuns(ctx context.Context, req GetCheckRunsRequest) (*GetCheckRunsResponse, error) {
+	return do[GetCheckRunsRequest, GetCheckRunsResponse](ctx, c, http.MethodGet, "get_check_runs", &req)
+}
+
+// Failed Reports whether the check run finished and did not pass, either
+// because the check failed or because it errored. Runs that are pending or
+// were skipped, and runs with no status whose results were never evaluated,
+// are not failed.
+func (r *CheckRun) Failed() bool {
+	if r.ResultsPending {
+		return false
+	}
+	switch r.Status {
+	case RunStatusFail, RunStatusError:
+		return true
+	case RunStatusPass, RunStatusPending, RunStatusSkipped:
+		return false
+	}
+	return r.Results.Errored || (r.Results.evaluated() && !r.Results.Success)
+}
+
+// evaluated Reports whether the check produced any results at all.
+func (r *CheckRunResults) evaluated() bool {
+	return r.Success || r.Errored || r.EvaluatedMessage != "" || r.ExceptionMsg != "" ||
+		r.StatisticName != "" || r.SampleRowsBadSql != "" || r.SampleRowsBadCsvUrl != ""
+}
+
+// Covers Reports whether `t` falls within the interval's time period.
+func (i *Interval) Covers(t time.Time) bool {
+	return !t.Before(i.TimePeriodStart) && t.Before(i.TimePeriodEnd)
+}
@@ -0,0 +1,60 @@
+package anomalo
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListIntervals(t *testing.T) {
+	server := setupServer(
+		t,
+		"get_table_intervals?end=2023-02-01T00%3A00%3A00Z&start=2023-01-01T00%3A00%3A00Z&table_id=5",
+		`{"intervals": [{"interval_id": 1, "status": "pass", "time_period_start": "2023-01-01T00:00:00Z", `+
+			`"time_period_end": "2023-01-02T00:00:00Z"}]}`,
+		http.StatusOK,
+	)
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	resp, err := client.ListIntervals(ListIntervalsRequest{
+		TableID: 5,
+		Start:   time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:     time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.Nil(t, err)
+	assert.Len(t, resp.Intervals, 1)
+	assert.True(t, resp.Intervals[0].Covers(time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)))
+	assert.False(t, resp.Intervals[0].Covers(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestGetCheckRuns(t *testing.T) {
+	server := setupServer(
+		t,
+		"get_check_runs?check_ids=3&check_ids=4&interval_id=9&table_id=5",
+		`{"check_runs": [
+			{"check_id": 3, "status": "pass", "results": {"success": true}},
+			{"check_id": 4, "status": "fail", "CreatedBy": {"id": 1, "name": "someone"},
+			 "results": {"success": false, "sample_rows_bad_csv_url": "/bad.csv", "statistic": 12.5}},
+			{"check_id": 5, "status": "skipped", "results": {"success": false}},
+			{"check_id": 6, "results": {}},
+			{"check_id": 7, "results": {"success": false, "evaluated_message": "3 nulls"}}
+		]}`,
+		http.StatusOK,
+	)
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	resp, err := client.GetCheckRuns(GetCheckRunsRequest{TableID: 5, IntervalID: 9, CheckIDs: []int{3, 4}})
+	assert.Nil(t, err)
+	assert.False(t, resp.CheckRuns[0].Failed())
+	assert.True(t, resp.CheckRuns[1].Failed())
+	assert.False(t, resp.CheckRuns[2].Failed(), "a skipped run did not fail")
+	assert.False(t, resp.CheckRuns[3].Failed(), "a run with no results did not fail")
+	assert.True(t, resp.CheckRuns[4].Failed())
+	assert.Equal(t, "/bad.csv", resp.CheckRuns[1].Results.SampleRowsBadCsvUrl)
+	assert.Equal(t, float32(12.5), resp.CheckRuns[1].Results.Statistic)
+	assert.Equal(t, "someone", resp.CheckRuns[1].CreatedBy.Name)
+}
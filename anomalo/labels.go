@@ -0,0 +1,116 @@
+package anomalo
+
+import (
+	"context"
+	"net/http"
+)
+
+func (c *Client) ListLabels() (*ListLabelsResponse, error) {
+	return do[struct{}, ListLabelsResponse](context.Background(), c, http.MethodGet, "list_labels", nil)
+}
+
+func (c *Client) CreateLabel(req CreateLabelRequest) (*Label, error) {
+	return do[CreateLabelRequest, Label](context.Background(), c, http.MethodPost, "create_label", &req)
+}
+
+// DeleteLabel Deletes a label and detaches it from every check and table.
+func (c *Client) DeleteLabel(labelID int) (*DeleteLabelResponse, error) {
+	req := DeleteLabelRequest{LabelID: labelID}
+	return do[DeleteLabelRequest, DeleteLabelResponse](context.Background(), c, http.MethodPost, "delete_label", &req)
+}
+
+// GetLabelByName Wrapper around ListLabels that looks for a label whose name
+// or slug is exactly `name`. Returns nil if no label matches.
+func (c *Client) GetLabelByName(name string) (*Label, error) {
+	data, err := c.ListLabels()
+	if err != nil {
+		return nil, err
+	}
+	for _, label := range data.Labels {
+		if label.Matches(name) {
+			return label, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) AddLabelsToCheck(req CheckLabelsRequest) (*LabelsResponse, error) {
+	return do[CheckLabelsRequest, LabelsResponse](context.Background(), c, http.MethodPost, "add_labels_to_check", &req)
+}
+
+func (c *Client) RemoveLabelsFromCheck(req CheckLabelsRequest) (*LabelsResponse, error) {
+	return do[CheckLabelsRequest, LabelsResponse](context.Background(), c, http.MethodPost, "remove_labels_from_check", &req)
+}
+
+func (c *Client) AddLabelsToTable(req TableLabelsRequest) (*LabelsResponse, error) {
+	return do[TableLabelsRequest, LabelsResponse](context.Background(), c, http.MethodPost, "add_labels_to_table", &req)
+}
+
+func (c *Client) RemoveLabelsFromTable(req TableLabelsRequest) (*LabelsResponse, error) {
+	return do[TableLabelsRequest, LabelsResponse](context.Background(), c, http.MethodPost, "remove_labels_from_table", &req)
+}
+
+// GetChecksWithLabels Wrapper around GetChecks that only returns the checks
+// carrying every one of `labels`, matched by name or slug.
+//
+// Like GetCheckByRef, this filters on the client, since the Anomalo API does
+// not allow queries by label.
+func (c *Client) GetChecksWithLabels(tableID int, labels ...string) ([]Check, error) {
+	data, err := c.GetChecks(tableID)
+	if err != nil {
+		return nil, err
+	}
+	return FilterChecksByLabel(data.Checks, labels...), nil
+}
+
+// Matches Reports whether the label's name or slug is exactly `name`.
+func (l *Label) Matches(name string) bool {
+	return l.Name == name || l.Slug == name
+}
+
+// HasLabel Reports whether the check carries a label whose name or slug is
+// exactly `name`.
+func (c *Check) HasLabel(name string) bool {
+	for _, label := range c.Labels {
+		if label.Matches(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterChecksByLabel Returns the checks carrying every one of `labels`,
+// matched by name or slug. With no labels, every check is returned.
+func FilterChecksByLabel(checks []Check, labels ...string) []Check {
+	var matching []Check
+	for _, check := range checks {
+		hasAll := true
+		for _, label := range labels {
+			if !check.HasLabel(label) {
+				hasAll = false
+				break
+			}
+		}
+		if hasAll {
+			matching = append(matching, check)
+		}
+	}
+	return matching
+}
+
+// GroupChecksByLabel Groups checks by the names of their labels, e.g. to build
+// a report of the checks each team owns. A check with several labels appears
+// in each of their groups, and checks without labels are grouped under "".
+func GroupChecksByLabel(checks []Check) map[string][]Check {
+	groups := map[string][]Check{}
+	for _, check := range checks {
+		if len(check.Labels) == 0 {
+			groups[""] = append(groups[""], check)
+			continue
+		}
+		for _, label := range check.Labels {
+			groups[label.Name] = append(groups[label.Name], check)
+		}
+	}
+	return groups
+}
@@ -0,0 +1,96 @@
+package anomalo
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const labeledChecks = `{"checks": [
+	{"check_id": 1, "labels": [{"id": 1, "name": "team:payments", "slug": "team-payments"}, {"id": 2, "name": "domain:orders"}]},
+	{"check_id": 2, "labels": [{"id": 1, "name": "team:payments", "slug": "team-payments"}]},
+	{"check_id": 3}
+]}`
+
+func TestGetChecksWithLabels(t *testing.T) {
+	server := setupServer(t, "get_checks_for_table?table_id=5", labeledChecks, http.StatusOK)
+	defer server.Close()
+	client := &Client{Host: server.URL}
+
+	checks, err := client.GetChecksWithLabels(5, "team-payments")
+	assert.Nil(t, err)
+	assert.Len(t, checks, 2)
+
+	checks, err = client.GetChecksWithLabels(5, "team:payments", "domain:orders")
+	assert.Nil(t, err)
+	assert.Len(t, checks, 1)
+	assert.Equal(t, 1, checks[0].CheckID)
+
+	data, err := client.GetChecks(5)
+	assert.Nil(t, err)
+	groups := GroupChecksByLabel(data.Checks)
+	assert.Len(t, groups["team:payments"], 2)
+	assert.Len(t, groups["domain:orders"], 1)
+	assert.Equal(t, 3, groups[""][0].CheckID)
+}
+
+func TestCheckLabelsInvalidateCache(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/get_checks_for_table":
+			w.Write([]byte(labeledChecks))
+		case "/api/public/v1/add_labels_to_check", "/api/public/v1/delete_label":
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			w.Write([]byte(`{"labels": [{"id": 4, "name": "new"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := &Client{Host: server.URL, Cache: NewResponseCache(time.Minute)}
+
+	_, err := client.GetChecks(5)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, client.Cache.Stats().Entries)
+
+	labels, err := client.AddLabelsToCheck(CheckLabelsRequest{TableID: 5, CheckID: 1, LabelIDs: []int{4}})
+	assert.Nil(t, err)
+	assert.Equal(t, "new", labels.Labels[0].Name)
+	assert.Equal(t, 0, client.Cache.Stats().Entries)
+
+	_, err = client.GetChecks(5)
+	assert.Nil(t, err)
+	_, err = client.DeleteLabel(4)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, client.Cache.Stats().Entries)
+
+	assert.Equal(t, []string{`{"table_id":5,"check_id":1,"label_ids":[4]}`, `{"label_id":4}`}, bodies)
+}
+
+func TestGetLabelByName(t *testing.T) {
+	server := setupServer(t, "list_labels", `{"labels": [{"id": 1, "name": "team:payments", "slug": "team-payments", "scope": "check"}]}`, http.StatusOK)
+	defer server.Close()
+	client := &Client{Host: server.URL}
+
+	label, err := client.GetLabelByName("team-payments")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, label.ID)
+	assert.Equal(t, LabelScopeCheck, label.Scope)
+
+	label, err = client.GetLabelByName("missing")
+	assert.Nil(t, err)
+	assert.Nil(t, label)
+}
+
+func TestCreateLabelBody(t *testing.T) {
+	body, err := json.Marshal(CreateLabelRequest{Name: "team:payments", Scope: LabelScopeTable})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name":"team:payments","scope":"table"}`, string(body))
+}
@@ -0,0 +1,177 @@
+package anomalo
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+const (
+	ConfigChangePauseMonitoring = "pause_monitoring"
+	ConfigChangeMuteChecks      = "mute_checks"
+)
+
+// ConfigChange A reversible change to a table's configuration, made by
+// PauseMonitoring or MuteChecks. It records what was changed so that
+// RestoreConfigChange can undo exactly that, and it can be stored as JSON so
+// that a scheduled job can restore it later (see RestoreExpiredConfigChanges).
+type ConfigChange struct {
+	TableID int    `json:"table_id"`
+	Kind    string `json:"kind"`
+	// PreviousCheckCadenceType The cadence the table had before monitoring
+	// was paused.
+	PreviousCheckCadenceType string `json:"previous_check_cadence_type,omitempty"`
+	// MutedCheckIDs The checks this change disabled. Checks that were already
+	// disabled are not included, so restoring the change leaves them disabled.
+	MutedCheckIDs []int     `json:"muted_check_ids,omitempty"`
+	AppliedAt     time.Time `json:"applied_at"`
+	// ExpiresAt When the change should be restored. Zero means never.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// Expired Reports whether the change has an expiry that is at or before `now`.
+func (cc *ConfigChange) Expired(now time.Time) bool {
+	return !cc.ExpiresAt.IsZero() && !now.Before(cc.ExpiresAt)
+}
+
+// currentTableRequest Reads a table's configuration and converts it to a
+// ConfigureTableRequest, so that a change to one setting can be applied
+// without resetting the others.
+func (c *Client) currentTableRequest(tableID int) (ConfigureTableRequest, error) {
+	table, err := c.GetTableInformationFromRequest(GetTableInformationRequest{TableID: tableID})
+	if err != nil {
+		return ConfigureTableRequest{}, err
+	}
+	req := table.Config.ToConfigureTableRequest()
+	req.TableID = tableID
+	return req, nil
+}
+
+// PauseMonitoring Stops scheduled checks on a table by clearing its check
+// cadence. Every other setting is kept. Restore the returned change to resume
+// monitoring with the previous cadence.
+func (c *Client) PauseMonitoring(tableID int) (*ConfigChange, error) {
+	req, err := c.currentTableRequest(tableID)
+	if err != nil {
+		return nil, err
+	}
+	cadence, ok := req.CheckCadenceType.Get()
+	if !ok {
+		return nil, fmt.Errorf("monitoring is already paused for table %d", tableID)
+	}
+	change := &ConfigChange{
+		TableID:                  tableID,
+		Kind:                     ConfigChangePauseMonitoring,
+		PreviousCheckCadenceType: cadence,
+		AppliedAt:                time.Now(),
+	}
+	req.CheckCadenceType = Null[string]()
+	if _, err := c.ConfigureTable(req); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// MuteChecks Disables quality checks on a table, keeping every other setting.
+// If `duration` is positive the returned change expires after it, so a
+// scheduled job calling RestoreExpiredConfigChanges can unmute the checks
+// after a maintenance window.
+func (c *Client) MuteChecks(tableID int, checkIDs []int, duration time.Duration) (*ConfigChange, error) {
+	req, err := c.currentTableRequest(tableID)
+	if err != nil {
+		return nil, err
+	}
+	change := &ConfigChange{TableID: tableID, Kind: ConfigChangeMuteChecks, AppliedAt: time.Now()}
+	if duration > 0 {
+		change.ExpiresAt = change.AppliedAt.Add(duration)
+	}
+	current, _ := req.DisabledQualityCheckIds.Get()
+	disabled := slices.Clone(current)
+	for _, checkID := range checkIDs {
+		if !slices.Contains(disabled, checkID) {
+			disabled = append(disabled, checkID)
+			change.MutedCheckIDs = append(change.MutedCheckIDs, checkID)
+		}
+	}
+	if len(change.MutedCheckIDs) == 0 {
+		return change, nil // Everything was already muted
+	}
+	req.DisabledQualityCheckIds = Set(disabled)
+	if _, err := c.ConfigureTable(req); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// UnmuteChecks Re-enables quality checks on a table, keeping every other
+// setting.
+func (c *Client) UnmuteChecks(tableID int, checkIDs []int) error {
+	req, err := c.currentTableRequest(tableID)
+	if err != nil {
+		return err
+	}
+	current, _ := req.DisabledQualityCheckIds.Get()
+	disabled := []int{} // Sent as an empty list when every check is unmuted
+	for _, checkID := range current {
+		if !slices.Contains(checkIDs, checkID) {
+			disabled = append(disabled, checkID)
+		}
+	}
+	if len(disabled) == len(current) {
+		return nil // None of the checks were muted
+	}
+	req.DisabledQualityCheckIds = Set(disabled)
+	_, err = c.ConfigureTable(req)
+	return err
+}
+
+// RestoreConfigChange Undoes a change made by PauseMonitoring or MuteChecks.
+//
+// Only the recorded change is undone. Monitoring is not resumed if someone
+// has already resumed it with a different cadence, and checks that were
+// muted separately stay muted.
+func (c *Client) RestoreConfigChange(change *ConfigChange) error {
+	switch change.Kind {
+	case ConfigChangePauseMonitoring:
+		req, err := c.currentTableRequest(change.TableID)
+		if err != nil {
+			return err
+		}
+		if req.CheckCadenceType.IsSet() {
+			return nil // Already resumed
+		}
+		req.CheckCadenceType = Set(change.PreviousCheckCadenceType)
+		_, err = c.ConfigureTable(req)
+		return err
+	case ConfigChangeMuteChecks:
+		if len(change.MutedCheckIDs) == 0 {
+			return nil
+		}
+		return c.UnmuteChecks(change.TableID, change.MutedCheckIDs)
+	default:
+		return fmt.Errorf("unknown config change kind %q", change.Kind)
+	}
+}
+
+// RestoreExpiredConfigChanges Restores every change that has expired as of
+// `now`, and returns the changes that are still pending: those that have not
+// expired, and those that failed to restore. Intended to be run by a
+// scheduled job that stores the pending changes between runs.
+func (c *Client) RestoreExpiredConfigChanges(changes []*ConfigChange, now time.Time) ([]*ConfigChange, error) {
+	var pending []*ConfigChange
+	var firstErr error
+	for _, change := range changes {
+		if !change.Expired(now) {
+			pending = append(pending, change)
+			continue
+		}
+		if err := c.RestoreConfigChange(change); err != nil {
+			pending = append(pending, change)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("unable to restore %s on table %d. %w", change.Kind, change.TableID, err)
+			}
+		}
+	}
+	return pending, firstErr
+}
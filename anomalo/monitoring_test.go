@@ -0,0 +1,147 @@
+package anomalo
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeTableServer Serves one table whose configuration is replaced by each
+// configure_table call, the way Anomalo resets omitted fields.
+func newFakeTableServer(t *testing.T, config *TableConfig) *httptest.Server {
+	return newFakeConfigServer(t, config, func(req ConfigureTableRequest) {
+		cadence, _ := req.CheckCadenceType.Get()
+		definition, _ := req.Definition.Get()
+		notifyAfter, _ := req.NotifyAfter.Get()
+		disabled, _ := req.DisabledQualityCheckIds.Get()
+		*config = TableConfig{
+			TableID:                 req.TableID,
+			CheckCadenceType:        cadence,
+			Definition:              definition,
+			NotifyAfter:             notifyAfter,
+			DisabledQualityCheckIds: disabled,
+		}
+	})
+}
+
+// newFakePartialTableServer Serves one table whose configuration is updated by
+// each configure_table call. Fields a call leaves unset are unchanged.
+func newFakePartialTableServer(t *testing.T, config *TableConfig) *httptest.Server {
+	return newFakeConfigServer(t, config, func(req ConfigureTableRequest) {
+		*config = TableConfig{
+			TableID:                 config.TableID,
+			CheckCadenceType:        desiredValue(req.CheckCadenceType, config.CheckCadenceType),
+			Definition:              desiredValue(req.Definition, config.Definition),
+			NotifyAfter:             desiredValue(req.NotifyAfter, config.NotifyAfter),
+			DisabledQualityCheckIds: desiredValue(req.DisabledQualityCheckIds, config.DisabledQualityCheckIds),
+		}
+	})
+}
+
+func newFakeConfigServer(t *testing.T, config *TableConfig, configure func(req ConfigureTableRequest)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/get_table_information":
+			body, _ := json.Marshal(GetTableResponse{ID: config.TableID, Config: *config})
+			w.Write(body)
+		case "/api/public/v1/configure_table":
+			body, _ := io.ReadAll(r.Body)
+			var req ConfigureTableRequest
+			assert.Nil(t, json.Unmarshal(body, &req))
+			configure(req)
+			w.Write([]byte(`{"id": 5}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+// fakeTableServers The configure_table semantics the helpers are tested
+// against.
+var fakeTableServers = map[string]func(t *testing.T, config *TableConfig) *httptest.Server{
+	"full replace":   newFakeTableServer,
+	"partial update": newFakePartialTableServer,
+}
+
+func TestPauseMonitoring(t *testing.T) {
+	for name, newServer := range fakeTableServers {
+		t.Run(name, func(t *testing.T) {
+			config := &TableConfig{TableID: 5, CheckCadenceType: "daily", Definition: "keep me", NotifyAfter: "1h"}
+			server := newServer(t, config)
+			defer server.Close()
+			client := &Client{Host: server.URL}
+
+			change, err := client.PauseMonitoring(5)
+			assert.Nil(t, err)
+			assert.Equal(t, "", config.CheckCadenceType)
+			assert.Equal(t, "keep me", config.Definition)
+			assert.Equal(t, "1h", config.NotifyAfter)
+			assert.Equal(t, "daily", change.PreviousCheckCadenceType)
+
+			_, err = client.PauseMonitoring(5)
+			assert.NotNil(t, err)
+
+			// The change survives a round trip through JSON
+			stored, err := json.Marshal(change)
+			assert.Nil(t, err)
+			var loaded ConfigChange
+			assert.Nil(t, json.Unmarshal(stored, &loaded))
+
+			assert.Nil(t, client.RestoreConfigChange(&loaded))
+			assert.Equal(t, "daily", config.CheckCadenceType)
+			assert.Equal(t, "keep me", config.Definition)
+
+			// Restoring does not override a cadence set since
+			change, err = client.PauseMonitoring(5)
+			assert.Nil(t, err)
+			config.CheckCadenceType = "hourly"
+			assert.Nil(t, client.RestoreConfigChange(change))
+			assert.Equal(t, "hourly", config.CheckCadenceType)
+		})
+	}
+}
+
+func TestMuteChecks(t *testing.T) {
+	for name, newServer := range fakeTableServers {
+		t.Run(name, func(t *testing.T) {
+			config := &TableConfig{TableID: 5, CheckCadenceType: "daily", DisabledQualityCheckIds: []int{1}}
+			server := newServer(t, config)
+			defer server.Close()
+			client := &Client{Host: server.URL}
+
+			change, err := client.MuteChecks(5, []int{1, 2, 3}, time.Hour)
+			assert.Nil(t, err)
+			assert.Equal(t, []int{1, 2, 3}, config.DisabledQualityCheckIds)
+			assert.Equal(t, "daily", config.CheckCadenceType)
+			assert.Equal(t, []int{2, 3}, change.MutedCheckIDs)
+			assert.Equal(t, change.AppliedAt.Add(time.Hour), change.ExpiresAt)
+
+			// Not expired yet
+			pending, err := client.RestoreExpiredConfigChanges([]*ConfigChange{change}, change.AppliedAt)
+			assert.Nil(t, err)
+			assert.Equal(t, []*ConfigChange{change}, pending)
+			assert.Equal(t, []int{1, 2, 3}, config.DisabledQualityCheckIds)
+
+			// Check 1 was muted before, so it stays muted
+			pending, err = client.RestoreExpiredConfigChanges([]*ConfigChange{change}, change.ExpiresAt)
+			assert.Nil(t, err)
+			assert.Empty(t, pending)
+			assert.Equal(t, []int{1}, config.DisabledQualityCheckIds)
+			assert.Equal(t, "daily", config.CheckCadenceType)
+
+			assert.Nil(t, client.UnmuteChecks(5, []int{1}))
+			assert.Empty(t, config.DisabledQualityCheckIds)
+
+			// A failed restore stays pending
+			unknown := &ConfigChange{TableID: 5, Kind: "unknown", ExpiresAt: change.ExpiresAt}
+			pending, err = client.RestoreExpiredConfigChanges([]*ConfigChange{unknown}, change.ExpiresAt)
+			assert.NotNil(t, err)
+			assert.Equal(t, []*ConfigChange{unknown}, pending)
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package anomalo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// OnboardingPolicy Describes how OnboardTable should set up a table.
+type OnboardingPolicy struct {
+	CheckCadenceType string
+	TimeColumnType   string
+	TimeColumns      []string
+	NotifyAfter      string
+	FreshAfter       string
+
+	// NotificationChannelDescription If set, the table alerts the channel of
+	// type NotificationChannelType whose description contains this string.
+	// See GetNotificationChannelWithDescriptionContaining.
+	NotificationChannelDescription string
+	NotificationChannelType        string
+
+	// DefaultChecks Checks to create on the table. Each check must have a Ref,
+	// which is used to skip checks that already exist. TableID is filled in
+	// by OnboardTable.
+	DefaultChecks []CreateCheckRequest
+
+	// WarehouseID If set, the table is looked up within this warehouse, and a
+	// table that Anomalo does not know about yet (the lookup returns a 404) is
+	// discovered with WaitForWarehouseRefresh. Other lookup errors are
+	// returned as they are.
+	WarehouseID    int64
+	RefreshOptions WaitForWarehouseRefreshOptions
+}
+
+// OnboardingStepStatus The outcome of one step of OnboardTable.
+type OnboardingStepStatus string
+
+const (
+	OnboardingStepDone       OnboardingStepStatus = "done"
+	OnboardingStepSkipped    OnboardingStepStatus = "skipped"
+	OnboardingStepFailed     OnboardingStepStatus = "failed"
+	OnboardingStepRolledBack OnboardingStepStatus = "rolled_back"
+)
+
+// OnboardingStep One step taken (or skipped) by OnboardTable.
+type OnboardingStep struct {
+	Name   string
+	Status OnboardingStepStatus
+	Detail string
+	Err    error
+}
+
+func (s OnboardingStep) String() string {
+	line := fmt.Sprintf("[%s] %s", s.Status, s.Name)
+	if s.Detail != "" {
+		line += ": " + s.Detail
+	}
+	if s.Err != nil {
+		line += ": " + s.Err.Error()
+	}
+	return line
+}
+
+// OnboardingResult What OnboardTable did, step by step.
+type OnboardingResult struct {
+	Table         *GetTableResponse
+	Steps         []OnboardingStep
+	CreatedChecks []CreateCheckResponse
+}
+
+func (r *OnboardingResult) String() string {
+	lines := make([]string, len(r.Steps))
+	for i, step := range r.Steps {
+		lines[i] = step.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *OnboardingResult) record(name string, status OnboardingStepStatus, detail string, err error) {
+	r.Steps = append(r.Steps, OnboardingStep{Name: name, Status: status, Detail: detail, Err: err})
+}
+
+// OnboardTable Sets up monitoring for `tableName` according to `policy`.
+//
+// The steps are: find the table (discovering it in its warehouse if needed),
+// resolve the notification channel, configure the table, and create the
+// policy's default checks. Each step is skipped when the table is already in
+// the desired state, so OnboardTable can safely be re-run.
+//
+// If creating a check fails, the checks created by this call are deleted
+// before the error is returned. The table configuration is left in place.
+// The returned OnboardingResult is populated even when an error is returned.
+// An invalid policy is rejected before anything is looked up or changed.
+func (c *Client) OnboardTable(ctx context.Context, tableName string, policy OnboardingPolicy) (*OnboardingResult, error) {
+	result := &OnboardingResult{}
+	if err := policy.validate(); err != nil {
+		result.record("validate policy", OnboardingStepFailed, "", err)
+		return result, err
+	}
+
+	table, err := c.onboardingLookup(ctx, tableName, policy, result)
+	if err != nil {
+		return result, err
+	}
+	result.Table = table
+
+	var channelID int
+	if policy.NotificationChannelDescription != "" {
+		channel, err := c.GetNotificationChannelWithDescriptionContaining(
+			policy.NotificationChannelDescription, policy.NotificationChannelType)
+		if err == nil && channel == nil {
+			err = fmt.Errorf("no %s channel has a description containing %q",
+				policy.NotificationChannelType, policy.NotificationChannelDescription)
+		}
+		if err != nil {
+			result.record("resolve notification channel", OnboardingStepFailed, "", err)
+			return result, err
+		}
+		channelID = channel.ID
+		result.record("resolve notification channel", OnboardingStepDone, fmt.Sprintf("channel %d", channel.ID), nil)
+	} else {
+		result.record("resolve notification channel", OnboardingStepSkipped, "no channel in policy", nil)
+	}
+
+	req := onboardingConfigureRequest(table, policy, channelID)
+	if onboardingConfigMatches(table, req) {
+		result.record("configure table", OnboardingStepSkipped, "already configured", nil)
+	} else if _, err := c.ConfigureTable(req); err != nil {
+		result.record("configure table", OnboardingStepFailed, "", err)
+		return result, err
+	} else {
+		result.record("configure table", OnboardingStepDone, "", nil)
+	}
+
+	if err := c.onboardingCreateChecks(table.ID, policy.DefaultChecks, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// validate Checks that every default check has a Ref, so that a bad policy
+// fails before anything has to be rolled back.
+func (p OnboardingPolicy) validate() error {
+	for i, check := range p.DefaultChecks {
+		if check.Ref == "" {
+			return fmt.Errorf("default check %d (%s) has no Ref", i, check.CheckType)
+		}
+	}
+	return nil
+}
+
+func (c *Client) onboardingLookup(
+	ctx context.Context,
+	tableName string,
+	policy OnboardingPolicy,
+	result *OnboardingResult,
+) (*GetTableResponse, error) {
+	lookup := GetTableInformationRequest{TableName: tableName, WarehouseID: int(policy.WarehouseID)}
+	table, err := c.GetTableInformationFromRequest(lookup)
+	if err == nil {
+		result.record("look up table", OnboardingStepDone, fmt.Sprintf("table %d", table.ID), nil)
+		result.record("discover table", OnboardingStepSkipped, "table already known", nil)
+		return table, nil
+	}
+	// Only a table Anomalo doesn't know about is worth a warehouse refresh
+	var apiErr *APIError
+	if policy.WarehouseID == 0 || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		result.record("look up table", OnboardingStepFailed, "", err)
+		return nil, err
+	}
+
+	refresh, err := c.WaitForWarehouseRefresh(ctx, policy.WarehouseID, policy.RefreshOptions)
+	if err != nil {
+		result.record("discover table", OnboardingStepFailed, "", err)
+		return nil, err
+	}
+	result.record("discover table", OnboardingStepDone, fmt.Sprintf("%d new table(s)", len(refresh.NewTables)), nil)
+
+	table, err = c.GetTableInformationFromRequest(lookup)
+	if err != nil {
+		result.record("look up table", OnboardingStepFailed, "", err)
+		return nil, err
+	}
+	result.record("look up table", OnboardingStepDone, fmt.Sprintf("table %d", table.ID), nil)
+	return table, nil
+}
+
+// onboardingConfigureRequest Builds a ConfigureTableRequest from the table's
+// current configuration with the policy applied on top, so that settings the
+// policy doesn't mention are not reset.
+func onboardingConfigureRequest(table *GetTableResponse, policy OnboardingPolicy, channelID int) ConfigureTableRequest {
+	req := table.Config.ToConfigureTableRequest()
+	req.TableID = table.ID
+
+	if policy.CheckCadenceType != "" {
+		req.CheckCadenceType = Set(policy.CheckCadenceType)
+	}
+	if policy.TimeColumnType != "" {
+		req.TimeColumnType = Set(policy.TimeColumnType)
+	}
+	if len(policy.TimeColumns) > 0 {
+		req.TimeColumns = Set(policy.TimeColumns)
+	}
+	if policy.NotifyAfter != "" {
+		req.NotifyAfter = Set(policy.NotifyAfter)
+	}
+	if policy.FreshAfter != "" {
+		req.FreshAfter = Set(policy.FreshAfter)
+	}
+	if channelID != 0 {
+		req.NotificationChannelID = Set(channelID)
+	}
+	return req
+}
+
+func onboardingConfigMatches(table *GetTableResponse, req ConfigureTableRequest) bool {
+	return req.CheckCadenceType.IsSet() && DiffTableConfig(table.Config, req).Empty()
+}
+
+func (c *Client) onboardingCreateChecks(tableID int, checks []CreateCheckRequest, result *OnboardingResult) error {
+	if len(checks) == 0 {
+		result.record("create checks", OnboardingStepSkipped, "no default checks in policy", nil)
+		return nil
+	}
+
+	existing, err := c.GetChecks(tableID)
+	if err != nil {
+		result.record("create checks", OnboardingStepFailed, "unable to list existing checks", err)
+		return err
+	}
+	refs := map[string]struct{}{}
+	for _, check := range existing.Checks {
+		refs[check.Ref] = struct{}{}
+	}
+
+	for _, check := range checks {
+		name := fmt.Sprintf("create check %s", check.Ref)
+		if _, ok := refs[check.Ref]; ok {
+			result.record(name, OnboardingStepSkipped, "already exists", nil)
+			continue
+		}
+		check.TableID = tableID
+		created, err := c.CreateCheck(check)
+		if err != nil {
+			result.record(name, OnboardingStepFailed, "", err)
+			c.onboardingRollback(tableID, result)
+			return err
+		}
+		result.CreatedChecks = append(result.CreatedChecks, *created)
+		result.record(name, OnboardingStepDone, fmt.Sprintf("check %d", created.CheckID), nil)
+	}
+	return nil
+}
+
+// onboardingRollback Deletes the checks created so far. Failures are recorded
+// rather than returned so that the original error is what the caller sees.
+// Checks that could not be deleted are left in CreatedChecks.
+func (c *Client) onboardingRollback(tableID int, result *OnboardingResult) {
+	var remaining []CreateCheckResponse
+	for _, created := range result.CreatedChecks {
+		name := fmt.Sprintf("roll back check %s", created.CheckRef)
+		if _, err := c.DeleteCheck(DeleteCheckRequest{TableID: tableID, CheckID: created.CheckID}); err != nil {
+			result.record(name, OnboardingStepFailed, fmt.Sprintf("check %d", created.CheckID), err)
+			remaining = append(remaining, created)
+			continue
+		}
+		result.record(name, OnboardingStepRolledBack, fmt.Sprintf("check %d", created.CheckID), nil)
+	}
+	result.CreatedChecks = remaining
+}
@@ -0,0 +1,166 @@
+package anomalo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnboardTable(t *testing.T) {
+	var configured []ConfigureTableRequest
+	var created, deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/api/public/v1/get_table_information":
+			w.Write([]byte(`{"id": 5, "config": {"definition": "keep me", "interval_skip_expr": "skip"}}`))
+		case "/api/public/v1/list_notification_channels":
+			w.Write([]byte(`{"notification_channels": [{"id": 8, "channel_type": "slack", "description": "#data-alerts"}]}`))
+		case "/api/public/v1/configure_table":
+			var req ConfigureTableRequest
+			assert.Nil(t, json.Unmarshal(body, &req))
+			configured = append(configured, req)
+			w.Write([]byte(`{"id": 5}`))
+		case "/api/public/v1/get_checks_for_table":
+			w.Write([]byte(`{"checks": [{"check_id": 1, "ref": "existing"}]}`))
+		case "/api/public/v1/create_check":
+			var req CreateCheckRequest
+			assert.Nil(t, json.Unmarshal(body, &req))
+			if req.Ref == "broken" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`invalid params`))
+				return
+			}
+			created = append(created, req.Ref)
+			fmt.Fprintf(w, `{"check_id": %d, "ref": %q}`, 100+len(created), req.Ref)
+		case "/api/public/v1/delete_check":
+			var req DeleteCheckRequest
+			assert.Nil(t, json.Unmarshal(body, &req))
+			deleted = append(deleted, fmt.Sprint(req.CheckID))
+			w.Write([]byte(`{"deleted_count": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	policy := OnboardingPolicy{
+		CheckCadenceType:               "daily",
+		TimeColumns:                    []string{"created_at"},
+		NotificationChannelDescription: "data-alerts",
+		NotificationChannelType:        "slack",
+		DefaultChecks: []CreateCheckRequest{
+			{CheckType: "NullCheck", Ref: "existing"},
+			{CheckType: "NullCheck", Ref: "new"},
+		},
+	}
+
+	result, err := client.OnboardTable(context.Background(), "wh.schema.table", policy)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, result.Table.ID)
+	assert.Equal(t, []string{"new"}, created)
+	assert.Len(t, configured, 1)
+	assert.Equal(t, Set("daily"), configured[0].CheckCadenceType)
+	assert.Equal(t, Set(8), configured[0].NotificationChannelID)
+	assert.Equal(t, Set("keep me"), configured[0].Definition)
+	assert.Equal(t, Set("skip"), configured[0].IntervalSkipExpr)
+	assert.Equal(t, "[skipped] create check existing: already exists", result.Steps[len(result.Steps)-2].String())
+
+	// A failure rolls back the checks created by the failing call
+	created = nil
+	policy.DefaultChecks = append(policy.DefaultChecks, CreateCheckRequest{CheckType: "NullCheck", Ref: "broken"})
+	result, err = client.OnboardTable(context.Background(), "wh.schema.table", policy)
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"new"}, created)
+	assert.Equal(t, []string{"101"}, deleted)
+	assert.Empty(t, result.CreatedChecks)
+	assert.Equal(t, OnboardingStepRolledBack, result.Steps[len(result.Steps)-1].Status)
+}
+
+func TestOnboardTableLookup(t *testing.T) {
+	lookupStatus := http.StatusNotFound
+	known := false
+	refreshes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/get_table_information":
+			if !known {
+				w.WriteHeader(lookupStatus)
+				w.Write([]byte(`lookup failed`))
+				return
+			}
+			w.Write([]byte(`{"id": 5, "config": {"check_cadence_type": "daily"}}`))
+		case "/api/public/v1/list_tables":
+			w.Write([]byte(`{"tables": []}`))
+		case "/api/public/v1/warehouse/3/refresh/new":
+			refreshes++
+			known = true
+			w.Write([]byte(`{"id": 3}`))
+		case "/api/public/v1/warehouse/3":
+			fmt.Fprintf(w, `{"id": 3, "last_partial_refreshed": %q}`, time.Now().Format(time.RFC3339Nano))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	policy := OnboardingPolicy{
+		CheckCadenceType: "daily",
+		WarehouseID:      3,
+		RefreshOptions:   WaitForWarehouseRefreshOptions{PollInterval: time.Millisecond},
+	}
+
+	// Errors other than not found are returned without a refresh
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError} {
+		lookupStatus = status
+		result, err := client.OnboardTable(context.Background(), "wh.schema.table", policy)
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, status, apiErr.StatusCode)
+		assert.Equal(t, "[failed] look up table: lookup failed", result.String())
+	}
+	assert.Equal(t, 0, refreshes)
+
+	lookupStatus = http.StatusNotFound
+	result, err := client.OnboardTable(context.Background(), "wh.schema.table", policy)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, refreshes)
+	assert.Equal(t, "[done] discover table: 0 new table(s)", result.Steps[0].String())
+	assert.Equal(t, "[done] look up table: table 5", result.Steps[1].String())
+
+	result, err = client.OnboardTable(context.Background(), "wh.schema.table", policy)
+	assert.Nil(t, err)
+	assert.Equal(t, "[done] look up table: table 5", result.Steps[0].String())
+	assert.Equal(t, "[skipped] discover table: table already known", result.Steps[1].String())
+}
+
+func TestOnboardTableRequiresRefs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected call to %s", r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	result, err := client.OnboardTable(context.Background(), "wh.schema.table", OnboardingPolicy{
+		CheckCadenceType: "daily",
+		DefaultChecks: []CreateCheckRequest{
+			{CheckType: "NullCheck", Ref: "first"},
+			{CheckType: "UniqueCheck"},
+		},
+	})
+	assert.EqualError(t, err, "default check 1 (UniqueCheck) has no Ref")
+	assert.Equal(t, "[failed] validate policy: default check 1 (UniqueCheck) has no Ref", result.String())
+	assert.Nil(t, result.Table)
+	assert.Empty(t, result.CreatedChecks)
+}
@@ -0,0 +1,178 @@
+package anomalo
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Optional A request field that distinguishes "leave unchanged", "clear" and
+// "set to a value", including the zero value. The zero Optional is unset and
+// is left out of the request; Null sends JSON null; Set sends the value.
+//
+//	ConfigureTableRequest{
+//		TableID:               5,
+//		AlwaysAlertOnErrors:   Set(false),  // sent as false
+//		NotificationChannelID: Null[int](), // sent as null
+//		// Fields not mentioned are not sent
+//	}
+//
+// Optional is used by the request types that update existing settings in
+// place, ConfigureTableRequest and UpdateWarehouseRequest. Other request
+// types create objects or start runs, where a zero value already means the
+// API default.
+type Optional[T any] struct {
+	value T
+	state optionalState
+}
+
+type optionalState uint8
+
+const (
+	optionalUnset optionalState = iota
+	optionalNull
+	optionalSet
+)
+
+// Set Creates an Optional holding `v`.
+func Set[T any](v T) Optional[T] {
+	return Optional[T]{value: v, state: optionalSet}
+}
+
+// Null Creates an Optional that clears the field.
+func Null[T any]() Optional[T] {
+	return Optional[T]{state: optionalNull}
+}
+
+// IsSet Reports whether the Optional holds a value.
+func (o Optional[T]) IsSet() bool {
+	return o.state == optionalSet
+}
+
+// IsNull Reports whether the Optional clears the field.
+func (o Optional[T]) IsNull() bool {
+	return o.state == optionalNull
+}
+
+// IsUnset Reports whether the Optional leaves the field unchanged.
+func (o Optional[T]) IsUnset() bool {
+	return o.state == optionalUnset
+}
+
+// Get Returns the value and true if the Optional is set, or the zero value
+// and false otherwise.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.state == optionalSet
+}
+
+func (o Optional[T]) isUnset() bool {
+	return o.IsUnset()
+}
+
+// MarshalJSON Encodes a set Optional as its value and anything else as null.
+// A set nil slice or map is encoded as an empty list or object, so that
+// Set([]int(nil)) empties a list rather than clearing it. Unset fields are
+// only left out by the MarshalJSON methods of the request types that contain
+// them; see marshalOmittingUnset.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if o.state != optionalSet {
+		return []byte("null"), nil
+	}
+	switch v := reflect.ValueOf(&o.value).Elem(); {
+	case v.Kind() == reflect.Slice && v.IsNil():
+		return []byte("[]"), nil
+	case v.Kind() == reflect.Map && v.IsNil():
+		return []byte("{}"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// UnmarshalJSON Decodes null as Null and anything else as Set. Fields missing
+// from the JSON stay unset.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		*o = Null[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*o = Set(v)
+	return nil
+}
+
+// optionalField Implemented by every Optional.
+type optionalField interface {
+	isUnset() bool
+}
+
+// marshalOmittingUnset Encodes the struct `v` like json.Marshal does, except
+// that unset Optional fields are left out. encoding/json's omitempty cannot
+// do this for struct-typed fields.
+func marshalOmittingUnset(v interface{}) ([]byte, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	rt := rv.Type()
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fv := rv.Field(i)
+		if opt, ok := fv.Interface().(optionalField); ok && opt.isUnset() {
+			continue
+		}
+		if strings.Contains(","+options+",", ",omitempty,") && isEmptyJSONValue(fv) {
+			continue
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(fv.Interface())
+		if err != nil {
+			return nil, err
+		}
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// isEmptyJSONValue Matches encoding/json's definition of empty for omitempty.
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+func (r ConfigureTableRequest) MarshalJSON() ([]byte, error) {
+	return marshalOmittingUnset(r)
+}
+
+func (r UpdateWarehouseRequest) MarshalJSON() ([]byte, error) {
+	return marshalOmittingUnset(r)
+}
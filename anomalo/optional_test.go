@@ -0,0 +1,103 @@
+package anomalo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionalStates(t *testing.T) {
+	var unset Optional[int]
+	assert.True(t, unset.IsUnset())
+	assert.False(t, unset.IsSet())
+	assert.False(t, unset.IsNull())
+
+	null := Null[int]()
+	assert.True(t, null.IsNull())
+	_, ok := null.Get()
+	assert.False(t, ok)
+
+	zero := Set(0)
+	assert.True(t, zero.IsSet())
+	value, ok := zero.Get()
+	assert.True(t, ok)
+	assert.Equal(t, 0, value)
+}
+
+func TestConfigureTableRequestMarshalsOptionals(t *testing.T) {
+	cases := []struct {
+		name string
+		req  ConfigureTableRequest
+		want string
+	}{
+		{"unset", ConfigureTableRequest{TableID: 5}, `{"table_id":5}`},
+		{
+			"null",
+			ConfigureTableRequest{
+				TableID:               5,
+				CheckCadenceType:      Null[string](),
+				NotificationChannelID: Null[int](),
+				TimeColumns:           Null[[]string](),
+			},
+			`{"table_id":5,"check_cadence_type":null,"notification_channel_id":null,"time_columns":null}`,
+		},
+		{
+			"zero values",
+			ConfigureTableRequest{
+				TableID:                 5,
+				NotifyAfter:             Set(""),
+				AlwaysAlertOnErrors:     Set(false),
+				DisabledQualityCheckIds: Set([]int{}),
+			},
+			`{"table_id":5,"notify_after":"","always_alert_on_errors":false,"disabled_quality_check_ids":[]}`,
+		},
+		{
+			"values",
+			ConfigureTableRequest{TableID: 5, AlwaysAlertOnErrors: Set(true), TimeColumns: Set([]string{"created_at"})},
+			`{"table_id":5,"time_columns":["created_at"],"always_alert_on_errors":true}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := json.Marshal(tc.req)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, string(body))
+
+			// Pointers marshal the same way, and decoding restores each state
+			body, err = json.Marshal(&tc.req)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, string(body))
+			var decoded ConfigureTableRequest
+			assert.Nil(t, json.Unmarshal(body, &decoded))
+			assert.Equal(t, tc.req, decoded)
+		})
+	}
+}
+
+func TestOptionalSetNilEncodesEmpty(t *testing.T) {
+	body, err := json.Marshal(ConfigureTableRequest{
+		TimeColumns:             Set([]string(nil)),
+		DisabledQualityCheckIds: Set([]int(nil)),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"time_columns":[],"disabled_quality_check_ids":[]}`, string(body))
+
+	body, err = json.Marshal(Set(map[string]int(nil)))
+	assert.Nil(t, err)
+	assert.Equal(t, `{}`, string(body))
+
+	body, err = json.Marshal(Null[[]string]())
+	assert.Nil(t, err)
+	assert.Equal(t, `null`, string(body))
+}
+
+func TestUpdateWarehouseRequestMarshalsOptionals(t *testing.T) {
+	body, err := json.Marshal(UpdateWarehouseRequest{
+		ID:                       3,
+		IsActive:                 Set(false),
+		SchemaCrawlExclusionList: Set([]string{}),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"is_active":false,"schema_crawl_exclusion_list":[]}`, string(body))
+}
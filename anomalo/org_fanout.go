@@ -0,0 +1,116 @@
+package anomalo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OrganizationResult The outcome of running a function within one
+// Organization. See ForEachOrganization.
+type OrganizationResult[T any] struct {
+	Organization *Organization
+	Value        T
+	Err          error
+}
+
+// OrganizationError An error returned by the function passed to
+// ForEachOrganization, along with the Organization it ran in.
+type OrganizationError struct {
+	Organization *Organization
+	Err          error
+}
+
+func (e *OrganizationError) Error() string {
+	return fmt.Sprintf("organization %s (%d): %s", e.Organization.Name, e.Organization.ID, e.Err.Error())
+}
+
+func (e *OrganizationError) Unwrap() error {
+	return e.Err
+}
+
+// OrganizationErrors Every per-organization failure from one
+// ForEachOrganization call.
+type OrganizationErrors []*OrganizationError
+
+func (e OrganizationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d organization(s) failed: %s", len(e), strings.Join(messages, "; "))
+}
+
+// RestoreOrganizationError Returned by ForEachOrganization when the original
+// organization could not be restored. Errors holds the per-organization
+// failures that would otherwise have been returned.
+type RestoreOrganizationError struct {
+	OrganizationID int64
+	Err            error
+	Errors         OrganizationErrors
+}
+
+func (e *RestoreOrganizationError) Error() string {
+	message := fmt.Sprintf("unable to restore organization %d. %s", e.OrganizationID, e.Err.Error())
+	if len(e.Errors) > 0 {
+		message += ". " + e.Errors.Error()
+	}
+	return message
+}
+
+func (e *RestoreOrganizationError) Unwrap() error {
+	return e.Err
+}
+
+// ForEachOrganization Runs `fn` within every organization the API key has
+// access to, one organization at a time.
+//
+// The organization this Client last changed to, with ChangeOrganization or an
+// OrgScopedClient, is restored afterward, even when `fn` fails. Anomalo has no
+// documented way to read the active organization, so if the Client has not
+// changed organization yet ForEachOrganization returns an error without
+// visiting any organization. A failure in one organization does not stop the others:
+// every organization gets a result, and if any of them failed the returned
+// error is an OrganizationErrors. If the original organization cannot be
+// restored, the error is a *RestoreOrganizationError carrying both, and the
+// results are still populated.
+//
+// ForEachOrganization holds the Client's organization lock for its whole run,
+// so `fn` must not call ChangeOrganization or use an OrgScopedClient built
+// from `c`.
+func ForEachOrganization[T any](c *Client, fn func(c *Client, org *Organization) (T, error)) ([]OrganizationResult[T], error) {
+	state := c.getState()
+	state.orgMu.Lock()
+	defer state.orgMu.Unlock()
+
+	original := state.activeOrg
+	if original == 0 {
+		return nil, fmt.Errorf("the active organization is unknown, so it could not be restored. " +
+			"Call ChangeOrganization first")
+	}
+	orgs, err := c.GetOrganizations()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]OrganizationResult[T], len(orgs))
+	var errs OrganizationErrors
+	for i, org := range orgs {
+		results[i].Organization = org
+		if err := c.ensureOrganization(int64(org.ID)); err != nil {
+			results[i].Err = err
+		} else {
+			results[i].Value, results[i].Err = fn(c, org)
+		}
+		if results[i].Err != nil {
+			errs = append(errs, &OrganizationError{Organization: org, Err: results[i].Err})
+		}
+	}
+
+	if err := c.ensureOrganization(original); err != nil {
+		return results, &RestoreOrganizationError{OrganizationID: original, Err: err, Errors: errs}
+	}
+	if len(errs) > 0 {
+		return results, errs
+	}
+	return results, nil
+}
@@ -0,0 +1,101 @@
+package anomalo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEachOrganization(t *testing.T) {
+	server := newFakeOrgServer(t)
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	_, err := client.ChangeOrganization(2)
+	assert.Nil(t, err)
+
+	results, err := ForEachOrganization(client, func(c *Client, org *Organization) (string, error) {
+		if org.ID == 3 {
+			return "", fmt.Errorf("boom")
+		}
+		checks, err := c.GetChecks(1)
+		if err != nil {
+			return "", err
+		}
+		return checks.Checks[0].Ref, nil
+	})
+
+	var orgErrs OrganizationErrors
+	assert.True(t, errors.As(err, &orgErrs))
+	assert.Len(t, orgErrs, 1)
+	assert.Equal(t, 3, orgErrs[0].Organization.ID)
+	assert.Equal(t, "org-1", results[0].Value)
+	assert.Equal(t, "org-2", results[1].Value)
+	assert.NotNil(t, results[2].Err)
+
+	checks, err := client.GetChecks(1)
+	assert.Nil(t, err)
+	assert.Equal(t, "org-2", checks.Checks[0].Ref, "the original organization should be restored")
+}
+
+func TestForEachOrganizationUnknownOrganization(t *testing.T) {
+	server := newFakeOrgServer(t)
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	_, err := ForEachOrganization(client, func(c *Client, org *Organization) (string, error) {
+		t.Fatal("no organization should be visited")
+		return "", nil
+	})
+	assert.NotNil(t, err)
+}
+
+func TestForEachOrganizationRestoreFailure(t *testing.T) {
+	changesToOrg2 := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/organization":
+			var req changeOrganizationRequest
+			body, _ := io.ReadAll(r.Body)
+			assert.Nil(t, json.Unmarshal(body, &req))
+			if req.ID == 2 {
+				// Only the first change to organization 2 succeeds
+				if changesToOrg2++; changesToOrg2 > 1 {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+			}
+			fmt.Fprintf(w, `{"id": %d}`, req.ID)
+		case "/api/public/v1/organizations":
+			w.Write([]byte(`[{"id": 1, "name": "org-1"}, {"id": 2, "name": "org-2"}, {"id": 3, "name": "org-3"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	_, err := client.ChangeOrganization(2)
+	assert.Nil(t, err)
+
+	results, err := ForEachOrganization(client, func(c *Client, org *Organization) (string, error) {
+		if org.ID == 3 {
+			return "", fmt.Errorf("boom")
+		}
+		return "ok", nil
+	})
+	var restoreErr *RestoreOrganizationError
+	assert.True(t, errors.As(err, &restoreErr))
+	assert.Equal(t, int64(2), restoreErr.OrganizationID)
+	assert.Len(t, restoreErr.Errors, 2, "the per-organization errors are kept")
+	assert.Equal(t, 2, restoreErr.Errors[0].Organization.ID)
+	assert.Equal(t, 3, restoreErr.Errors[1].Organization.ID)
+	assert.Contains(t, err.Error(), "2 organization(s) failed")
+	assert.Equal(t, "ok", results[0].Value)
+}
@@ -0,0 +1,55 @@
+package anomalo
+
+import "fmt"
+
+// OrgScopedClient Runs calls against a fixed Organization.
+//
+// An API key acts within one organization at a time, and ChangeOrganization
+// changes that organization for every user of the key. OrgScopedClient makes it
+// safe to share one Client between goroutines that target different
+// organizations: calls made through Do are serialized behind the Client's
+// organization lock, and the organization is changed, and the change
+// verified, before each call. The change is made even when this Client last
+// changed to the same organization, since another user of the API key may
+// have changed it since.
+//
+// Calls made directly on the underlying Client do not take the lock, so they
+// should not be mixed with OrgScopedClient calls for a different organization.
+type OrgScopedClient struct {
+	client         *Client
+	OrganizationID int64
+}
+
+// ForOrganization Returns an OrgScopedClient that runs calls within the
+// organization `orgID`. Scoped clients created from the same Client share a
+// lock, so they can be used concurrently.
+func (c *Client) ForOrganization(orgID int64) *OrgScopedClient {
+	return &OrgScopedClient{client: c, OrganizationID: orgID}
+}
+
+// Do Runs `fn` with the API key acting within the scoped organization. No other
+// scoped call or ChangeOrganization call on the same Client can run until `fn`
+// returns, so `fn` should not call ChangeOrganization itself.
+func (o *OrgScopedClient) Do(fn func(c *Client) error) error {
+	state := o.client.getState()
+	state.orgMu.Lock()
+	defer state.orgMu.Unlock()
+
+	if err := o.client.ensureOrganization(o.OrganizationID); err != nil {
+		return err
+	}
+	return fn(o.client)
+}
+
+// ensureOrganization Changes to organization `orgID` and checks that Anomalo
+// reports it as active. The caller must hold orgMu.
+func (c *Client) ensureOrganization(orgID int64) error {
+	changed, err := c.changeOrganization(orgID)
+	if err != nil {
+		return fmt.Errorf("unable to change to organization %d. %w", orgID, err)
+	}
+	if int64(changed.ID) != orgID {
+		return fmt.Errorf("asked to change to organization %d, but organization %d is active", orgID, changed.ID)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package anomalo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeOrgServer Returns a server that tracks the active organization the way
+// Anomalo does, and answers get_checks_for_table with a check whose ref names
+// the active organization.
+func newFakeOrgServer(t *testing.T) *httptest.Server {
+	var mu sync.Mutex
+	activeOrg := 1
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == "/api/public/v1/organization" && r.Method == http.MethodPut:
+			var req changeOrganizationRequest
+			body, _ := io.ReadAll(r.Body)
+			assert.Nil(t, json.Unmarshal(body, &req))
+			activeOrg = int(req.ID)
+			fmt.Fprintf(w, `{"id": %d}`, activeOrg)
+		case r.URL.Path == "/api/public/v1/organizations":
+			w.Write([]byte(`[{"id": 1, "name": "org-1"}, {"id": 2, "name": "org-2"}, {"id": 3, "name": "org-3"}]`))
+		case r.URL.Path == "/api/public/v1/get_checks_for_table":
+			fmt.Fprintf(w, `{"checks": [{"ref": "org-%d"}]}`, activeOrg)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestOrgScopedClientConcurrentOrganizations(t *testing.T) {
+	server := newFakeOrgServer(t)
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		orgID := int64(i%3 + 1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := client.ForOrganization(orgID).Do(func(c *Client) error {
+				checks, err := c.GetChecks(1)
+				if err != nil {
+					return err
+				}
+				assert.Equal(t, fmt.Sprintf("org-%d", orgID), checks.Checks[0].Ref)
+				return nil
+			})
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestClientMethodsConcurrently(t *testing.T) {
+	server := newFakeOrgServer(t)
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetOrganizations()
+			assert.Nil(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := client.GetCheckByRef(1, "org-1")
+			assert.Nil(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := client.ChangeOrganization(1)
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestClientCopy(t *testing.T) {
+	server := newFakeOrgServer(t)
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	_, err := client.ChangeOrganization(2)
+	assert.Nil(t, err)
+
+	// A copy made after first use shares the organization lock and state
+	copied := *client
+	copied.Token = "other"
+	assert.Same(t, client.getState(), copied.getState())
+	results, err := ForEachOrganization(&copied, func(c *Client, org *Organization) (int, error) {
+		return org.ID, nil
+	})
+	assert.Nil(t, err)
+	assert.Len(t, results, 3)
+}
+
+func TestClientStateCreatedOnce(t *testing.T) {
+	client := &Client{}
+	states := make([]*clientState, 8)
+	var wg sync.WaitGroup
+	for i := range states {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			states[i] = client.getState()
+		}(i)
+	}
+	wg.Wait()
+	for _, state := range states {
+		assert.Same(t, states[0], state)
+	}
+}
+
+func TestOrganizationChangeClearsCache(t *testing.T) {
+	server := newFakeOrgServer(t)
+	defer server.Close()
+
+	client := &Client{Host: server.URL, Cache: NewResponseCache(time.Minute)}
+	scoped := func(orgID int64) string {
+		var ref string
+		err := client.ForOrganization(orgID).Do(func(c *Client) error {
+			checks, err := c.GetChecks(1)
+			if err == nil {
+				ref = checks.Checks[0].Ref
+			}
+			return err
+		})
+		assert.Nil(t, err)
+		return ref
+	}
+
+	assert.Equal(t, "org-1", scoped(1))
+	assert.Equal(t, "org-1", scoped(1))
+	assert.Equal(t, uint64(1), client.Cache.Stats().Hits, "staying in one organization keeps the cache")
+	assert.Equal(t, "org-2", scoped(2))
+	assert.Equal(t, "org-1", scoped(1))
+	assert.Equal(t, uint64(1), client.Cache.Stats().Hits)
+}
@@ -0,0 +1,699 @@
+package anomalo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// PolicySeverity How serious a policy violation is. The values match SARIF
+// result levels.
+type PolicySeverity string
+
+const (
+	PolicySeverityError   PolicySeverity = "error"
+	PolicySeverityWarning PolicySeverity = "warning"
+	PolicySeverityNote    PolicySeverity = "note"
+)
+
+// Output formats for PolicyReport.Write.
+const (
+	PolicyFormatText  = "text"
+	PolicyFormatJSON  = "json"
+	PolicyFormatSARIF = "sarif"
+)
+
+// PolicySubject A table and its checks, as policy rules see them.
+type PolicySubject struct {
+	Table  *GetTableResponse
+	Checks []Check
+	// Channels The workspace's notification channels by ID, so rules can
+	// resolve where a check's alerts are routed.
+	Channels map[int]NotificationChannel
+}
+
+// CheckChannels The channels a check's alerts go to: the table's channel
+// plus the check's additional notification channel, if either is set.
+func (s *PolicySubject) CheckChannels(check *Check) []NotificationChannel {
+	var channels []NotificationChannel
+	if channel := s.TableChannel(); channel != nil {
+		channels = append(channels, *channel)
+	}
+	if id := check.AdditionalNotificationChannelID; id != 0 && (len(channels) == 0 || channels[0].ID != id) {
+		channel, ok := s.Channels[id]
+		if !ok {
+			channel = NotificationChannel{ID: id}
+		}
+		channels = append(channels, channel)
+	}
+	return channels
+}
+
+// TableChannel The channel a table's alerts go to, or nil if it has none.
+func (s *PolicySubject) TableChannel() *NotificationChannel {
+	if s.Table.NotificationChannel.ID != 0 {
+		return &s.Table.NotificationChannel
+	}
+	if id := s.Table.Config.NotificationChannelID; id != 0 {
+		if channel, ok := s.Channels[id]; ok {
+			return &channel
+		}
+		return &NotificationChannel{ID: id}
+	}
+	return nil
+}
+
+// PolicyViolation One place where a table or check breaks a rule.
+type PolicyViolation struct {
+	RuleID    string         `json:"rule_id"`
+	Severity  PolicySeverity `json:"severity"`
+	TableID   int            `json:"table_id"`
+	TableName string         `json:"table_name"`
+	// CheckStaticID Zero when the violation is about the table itself.
+	CheckStaticID int    `json:"check_static_id,omitempty"`
+	Message       string `json:"message"`
+}
+
+func (v PolicyViolation) location() string {
+	if v.CheckStaticID != 0 {
+		return fmt.Sprintf("%s check %d", v.TableName, v.CheckStaticID)
+	}
+	return v.TableName
+}
+
+// PolicyRule A rule that tables and checks must follow. Evaluate only needs to
+// set Message, and CheckStaticID for violations about a check; the other
+// fields are filled in from the rule and subject.
+type PolicyRule struct {
+	ID          string
+	Description string
+	// Severity Defaults to PolicySeverityError.
+	Severity PolicySeverity
+	Evaluate func(subject *PolicySubject) []PolicyViolation
+}
+
+func (r *PolicyRule) evaluate(subject *PolicySubject) []PolicyViolation {
+	severity := r.Severity
+	if severity == "" {
+		severity = PolicySeverityError
+	}
+	violations := r.Evaluate(subject)
+	for i := range violations {
+		violations[i].RuleID = r.ID
+		violations[i].Severity = severity
+		violations[i].TableID = subject.Table.ID
+		violations[i].TableName = subject.Table.FullName
+	}
+	return violations
+}
+
+// RequireNotificationChannel A rule that every monitored table has a
+// notification channel.
+func RequireNotificationChannel(id string) PolicyRule {
+	return PolicyRule{
+		ID:          id,
+		Description: "Every monitored table must have a notification channel",
+		Evaluate: func(s *PolicySubject) []PolicyViolation {
+			if !s.Table.Monitored || s.TableChannel() != nil {
+				return nil
+			}
+			return []PolicyViolation{{Message: "monitored table has no notification channel"}}
+		},
+	}
+}
+
+// RequireChannelTypeForPriority A rule that checks with the given priority
+// level send their alerts to a channel of the given type, e.g. "pagerduty".
+func RequireChannelTypeForPriority(id string, priority string, channelType string) PolicyRule {
+	return PolicyRule{
+		ID:          id,
+		Description: fmt.Sprintf("Checks with priority %s must route to %s", priority, channelType),
+		Evaluate: func(s *PolicySubject) []PolicyViolation {
+			var violations []PolicyViolation
+			for i := range s.Checks {
+				check := &s.Checks[i]
+				if check.Config.Metadata.PriorityLevel != priority {
+					continue
+				}
+				channels := s.CheckChannels(check)
+				if slices.ContainsFunc(channels, func(c NotificationChannel) bool { return c.ChannelType == channelType }) {
+					continue
+				}
+				got := "no notification channel"
+				if len(channels) > 0 {
+					routes := make([]string, len(channels))
+					for i, channel := range channels {
+						routes[i] = fmt.Sprintf("channel %d (%s)", channel.ID, channel.ChannelType)
+					}
+					got = strings.Join(routes, " and ")
+				}
+				violations = append(violations, PolicyViolation{
+					CheckStaticID: check.CheckStaticID,
+					Message:       fmt.Sprintf("%s check routes to %s, want %s", priority, got, channelType),
+				})
+			}
+			return violations
+		},
+	}
+}
+
+// MaxDisabledQualityChecks A rule that no table disables more than `max` of
+// Anomalo's built-in quality checks.
+func MaxDisabledQualityChecks(id string, max int) PolicyRule {
+	return PolicyRule{
+		ID:          id,
+		Description: fmt.Sprintf("Tables may disable at most %d quality checks", max),
+		Evaluate: func(s *PolicySubject) []PolicyViolation {
+			disabled := len(s.Table.Config.DisabledQualityCheckIds)
+			if disabled <= max {
+				return nil
+			}
+			return []PolicyViolation{{Message: fmt.Sprintf("%d quality checks are disabled, want at most %d", disabled, max)}}
+		},
+	}
+}
+
+// PolicyReport The outcome of evaluating rules against tables.
+type PolicyReport struct {
+	Rules      []PolicyRule
+	Violations []PolicyViolation
+	// Tables The number of tables evaluated.
+	Tables int
+	// Unread Tables that could not be read, and so were not evaluated.
+	Unread []Table
+}
+
+// EvaluatePolicies Evaluates every rule against every subject. Violations are
+// in subject order, then rule order.
+func EvaluatePolicies(subjects []*PolicySubject, rules []PolicyRule) *PolicyReport {
+	report := &PolicyReport{Rules: rules, Tables: len(subjects)}
+	for _, subject := range subjects {
+		for i := range rules {
+			report.Violations = append(report.Violations, rules[i].evaluate(subject)...)
+		}
+	}
+	return report
+}
+
+// LintWorkspace Evaluates rules against every monitored table in the active
+// organization. Tables are read concurrently, as controlled by `opts`, and
+// evaluated in order of name.
+//
+// If some tables can't be read, the rest are still evaluated: the report lists
+// the others in Unread and the error is a *BulkError.
+func (c *Client) LintWorkspace(rules []PolicyRule, opts BulkOptions) (*PolicyReport, error) {
+	channels, err := c.GetNotificationChannels()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list notification channels. %w", err)
+	}
+	channelsByID := map[int]NotificationChannel{}
+	for _, channel := range channels.NotificationChannels {
+		channelsByID[channel.ID] = channel
+	}
+
+	warehouses, err := c.ListWarehouses()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list warehouses. %w", err)
+	}
+	var tables []Table
+	for _, warehouse := range warehouses.Warehouses {
+		listed, err := c.ListTables(ListTablesRequest{WarehouseID: warehouse.ID})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list tables in warehouse %d. %w", warehouse.ID, err)
+		}
+		for _, table := range listed.Tables {
+			if table.Monitored {
+				tables = append(tables, table)
+			}
+		}
+	}
+	sort.SliceStable(tables, func(i, j int) bool { return tables[i].FullName < tables[j].FullName })
+
+	results := make([]BulkResult[PolicySubject], len(tables))
+	runBulk(tables, nil, results, opts, func(table Table) (*PolicySubject, error) {
+		info, err := c.GetTableInformationFromRequest(GetTableInformationRequest{TableID: table.ID})
+		if err != nil {
+			return nil, err
+		}
+		checks, err := c.GetChecks(table.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list checks for table %d. %w", table.ID, err)
+		}
+		return &PolicySubject{Table: info, Checks: checks.Checks, Channels: channelsByID}, nil
+	})
+
+	var subjects []*PolicySubject
+	var unread []Table
+	for i, result := range results {
+		if result.Err != nil {
+			unread = append(unread, tables[i])
+			continue
+		}
+		subjects = append(subjects, result.Response)
+	}
+	report := EvaluatePolicies(subjects, rules)
+	report.Unread = unread
+	return report, bulkError(results)
+}
+
+// Write Writes the report to `w` in one of the PolicyFormat formats.
+func (r *PolicyReport) Write(w io.Writer, format string) error {
+	switch format {
+	case PolicyFormatText:
+		_, err := io.WriteString(w, r.String()+"\n")
+		return err
+	case PolicyFormatJSON:
+		return r.writeJSON(w)
+	case PolicyFormatSARIF:
+		return r.writeSARIF(w)
+	default:
+		return fmt.Errorf("unknown policy report format %q", format)
+	}
+}
+
+// String Renders the report as text, one violation per line, e.g.
+//
+//	2 table(s), 1 violation(s)
+//	error: wh.sales.orders check 12: [high-pagerduty] high check routes to channel 3 (slack), want pagerduty
+func (r *PolicyReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d table(s), %d violation(s)", r.Tables, len(r.Violations))
+	for _, v := range r.Violations {
+		fmt.Fprintf(&b, "\n%s: %s: [%s] %s", v.Severity, v.location(), v.RuleID, v.Message)
+	}
+	for _, table := range r.Unread {
+		fmt.Fprintf(&b, "\nunread: %s", table.FullName)
+	}
+	return b.String()
+}
+
+func (r *PolicyReport) writeJSON(w io.Writer) error {
+	violations := r.Violations
+	if violations == nil {
+		violations = []PolicyViolation{}
+	}
+	unread := make([]string, len(r.Unread))
+	for i, table := range r.Unread {
+		unread[i] = table.FullName
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Tables     int               `json:"tables"`
+		Violations []PolicyViolation `json:"violations"`
+		Unread     []string          `json:"unread,omitempty"`
+	}{r.Tables, violations, unread})
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string        `json:"id"`
+	ShortDescription     *sarifMessage `json:"shortDescription,omitempty"`
+	DefaultConfiguration struct {
+		Level PolicySeverity `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     PolicySeverity  `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// writeSARIF Writes the violations as a SARIF 2.1.0 log. Tables and checks
+// have no source file, so results point at logical locations named after the
+// table, e.g. "wh.sales.orders/checks/12".
+func (r *PolicyReport) writeSARIF(w io.Writer) error {
+	driver := sarifDriver{Name: "anomalo-go", Rules: []sarifRule{}}
+	ruleIndexes := map[string]int{}
+	for _, rule := range r.Rules {
+		sr := sarifRule{ID: rule.ID}
+		if rule.Description != "" {
+			sr.ShortDescription = &sarifMessage{Text: rule.Description}
+		}
+		sr.DefaultConfiguration.Level = rule.Severity
+		if sr.DefaultConfiguration.Level == "" {
+			sr.DefaultConfiguration.Level = PolicySeverityError
+		}
+		ruleIndexes[rule.ID] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, sr)
+	}
+
+	results := []sarifResult{}
+	for _, v := range r.Violations {
+		location := sarifLogicalLocation{Name: v.TableName, FullyQualifiedName: v.TableName, Kind: "resource"}
+		if v.CheckStaticID != 0 {
+			location.Name = fmt.Sprintf("check %d", v.CheckStaticID)
+			location.FullyQualifiedName = fmt.Sprintf("%s/checks/%d", v.TableName, v.CheckStaticID)
+		}
+		results = append(results, sarifResult{
+			RuleID:    v.RuleID,
+			RuleIndex: ruleIndexes[v.RuleID],
+			Level:     v.Severity,
+			Message:   sarifMessage{Text: v.Message},
+			Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{location}}},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
+
+// PolicyRuleSpec A declarative rule, e.g.
+//
+//	rules:
+//	  - id: high-priority-pagerduty
+//	    description: High priority checks must route to PagerDuty
+//	    scope: check
+//	    where:
+//	      - field: priority_level
+//	        equals: high
+//	    require:
+//	      - field: notification_channel_type
+//	        equals: pagerduty
+//
+// A table or check breaks the rule when it matches every `where` condition
+// and fails a `require` condition. See PolicyTableFields and
+// PolicyCheckFields for the fields of each scope.
+type PolicyRuleSpec struct {
+	ID          string            `yaml:"id"`
+	Description string            `yaml:"description"`
+	Severity    PolicySeverity    `yaml:"severity"`
+	Scope       string            `yaml:"scope"`
+	Where       []PolicyCondition `yaml:"where"`
+	Require     []PolicyCondition `yaml:"require"`
+}
+
+// PolicyCondition A test on one field. Every operator that is set must hold.
+// On list fields, `equals` and `one_of` hold if any element matches, and
+// `max` and `min` compare the length. `max` and `min` only apply to number
+// and list fields.
+type PolicyCondition struct {
+	Field    string   `yaml:"field"`
+	Equals   *string  `yaml:"equals"`
+	OneOf    []string `yaml:"one_of"`
+	NotEmpty bool     `yaml:"not_empty"`
+	Max      *int     `yaml:"max"`
+	Min      *int     `yaml:"min"`
+}
+
+// PolicyTableFields The fields declarative rules with scope "table" can test.
+var PolicyTableFields = map[string]func(s *PolicySubject) interface{}{
+	"full_name":                 func(s *PolicySubject) interface{} { return s.Table.FullName },
+	"warehouse":                 func(s *PolicySubject) interface{} { return s.Table.Warehouse.Name },
+	"monitored":                 func(s *PolicySubject) interface{} { return s.Table.Monitored },
+	"check_cadence_type":        func(s *PolicySubject) interface{} { return s.Table.Config.CheckCadenceType },
+	"time_columns":              func(s *PolicySubject) interface{} { return s.Table.Config.TimeColumns },
+	"fresh_after":               func(s *PolicySubject) interface{} { return s.Table.Config.FreshAfter },
+	"notify_after":              func(s *PolicySubject) interface{} { return s.Table.Config.NotifyAfter },
+	"always_alert_on_errors":    func(s *PolicySubject) interface{} { return s.Table.Config.AlwaysAlertOnErrors },
+	"notification_channel_type": func(s *PolicySubject) interface{} { return channelType(s.TableChannel()) },
+	"disabled_quality_checks":   func(s *PolicySubject) interface{} { return len(s.Table.Config.DisabledQualityCheckIds) },
+	"checks":                    func(s *PolicySubject) interface{} { return len(s.Checks) },
+	"labels":                    func(s *PolicySubject) interface{} { return labelNames(s.Table.Labels) },
+}
+
+// PolicyCheckFields The fields declarative rules with scope "check" can test.
+// Table fields are available with a "table." prefix, e.g. "table.full_name".
+// A check's notification_channel_type lists the types of every channel its
+// alerts go to: the table's channel and its additional channel.
+var PolicyCheckFields = map[string]func(s *PolicySubject, check *Check) interface{}{
+	"check_type": func(s *PolicySubject, check *Check) interface{} {
+		if check.CheckType == "" {
+			return check.Config.Check
+		}
+		return check.CheckType
+	},
+	"ref":            func(s *PolicySubject, check *Check) interface{} { return check.Ref },
+	"priority_level": func(s *PolicySubject, check *Check) interface{} { return check.Config.Metadata.PriorityLevel },
+	"is_system_check": func(s *PolicySubject, check *Check) interface{} {
+		return check.Config.Metadata.IsSystemCheck
+	},
+	"notification_channel_type": func(s *PolicySubject, check *Check) interface{} {
+		channels := s.CheckChannels(check)
+		types := make([]string, len(channels))
+		for i, channel := range channels {
+			types[i] = channel.ChannelType
+		}
+		return types
+	},
+	"labels": func(s *PolicySubject, check *Check) interface{} { return labelNames(check.Labels) },
+}
+
+func channelType(channel *NotificationChannel) string {
+	if channel == nil {
+		return ""
+	}
+	return channel.ChannelType
+}
+
+func labelNames(labels []*Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if label != nil {
+			names = append(names, label.Name)
+		}
+	}
+	return names
+}
+
+// ParsePolicyRules Parses a YAML file of declarative rules under a top-level
+// `rules` key. Unknown keys and fields are rejected.
+func ParsePolicyRules(data []byte) ([]PolicyRule, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var file struct {
+		Rules []PolicyRuleSpec `yaml:"rules"`
+	}
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("unable to parse policy rules. %w", err)
+	}
+	rules := make([]PolicyRule, len(file.Rules))
+	for i, spec := range file.Rules {
+		rule, err := spec.Compile()
+		if err != nil && spec.ID == "" {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		} else if err != nil {
+			return nil, err
+		}
+		rules[i] = rule
+	}
+	return rules, nil
+}
+
+// LoadPolicyRules Reads and parses a file of declarative rules.
+func LoadPolicyRules(path string) ([]PolicyRule, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := ParsePolicyRules(contents)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// Compile Converts the spec to a PolicyRule.
+func (spec PolicyRuleSpec) Compile() (PolicyRule, error) {
+	if spec.ID == "" {
+		return PolicyRule{}, fmt.Errorf("rule has no id")
+	}
+	switch spec.Severity {
+	case "", PolicySeverityError, PolicySeverityWarning, PolicySeverityNote:
+	default:
+		return PolicyRule{}, fmt.Errorf("rule %s: unknown severity %q", spec.ID, spec.Severity)
+	}
+	if len(spec.Require) == 0 {
+		return PolicyRule{}, fmt.Errorf("rule %s: no require conditions", spec.ID)
+	}
+
+	if spec.Scope != "table" && spec.Scope != "check" {
+		return PolicyRule{}, fmt.Errorf("rule %s: scope must be table or check, not %q", spec.ID, spec.Scope)
+	}
+	resolve := func(s *PolicySubject, check *Check, field string) interface{} {
+		return policyField(spec.Scope, field)(s, check)
+	}
+	for _, condition := range append(append([]PolicyCondition{}, spec.Where...), spec.Require...) {
+		if err := condition.validate(spec.Scope); err != nil {
+			return PolicyRule{}, fmt.Errorf("rule %s: %w", spec.ID, err)
+		}
+	}
+
+	// evaluate Returns the message for the first failed requirement, or ""
+	evaluate := func(s *PolicySubject, check *Check) string {
+		for _, condition := range spec.Where {
+			if condition.failure(resolve(s, check, condition.Field)) != "" {
+				return ""
+			}
+		}
+		for _, condition := range spec.Require {
+			if failure := condition.failure(resolve(s, check, condition.Field)); failure != "" {
+				return failure
+			}
+		}
+		return ""
+	}
+	return PolicyRule{
+		ID:          spec.ID,
+		Description: spec.Description,
+		Severity:    spec.Severity,
+		Evaluate: func(s *PolicySubject) []PolicyViolation {
+			if spec.Scope == "table" {
+				if message := evaluate(s, nil); message != "" {
+					return []PolicyViolation{{Message: message}}
+				}
+				return nil
+			}
+			var violations []PolicyViolation
+			for i := range s.Checks {
+				if message := evaluate(s, &s.Checks[i]); message != "" {
+					violations = append(violations, PolicyViolation{CheckStaticID: s.Checks[i].CheckStaticID, Message: message})
+				}
+			}
+			return violations
+		},
+	}, nil
+}
+
+// policyField Looks up a field of the given scope, returning nil if there is
+// no such field.
+func policyField(scope string, field string) func(s *PolicySubject, check *Check) interface{} {
+	if scope == "check" && !strings.HasPrefix(field, "table.") {
+		if resolve, ok := PolicyCheckFields[field]; ok {
+			return resolve
+		}
+		return nil
+	}
+	if scope == "check" {
+		field = strings.TrimPrefix(field, "table.")
+	}
+	if resolve, ok := PolicyTableFields[field]; ok {
+		return func(s *PolicySubject, _ *Check) interface{} { return resolve(s) }
+	}
+	return nil
+}
+
+func (cond PolicyCondition) validate(scope string) error {
+	resolve := policyField(scope, cond.Field)
+	if resolve == nil {
+		return fmt.Errorf("unknown %s field %q", scope, cond.Field)
+	}
+	if cond.Equals == nil && cond.OneOf == nil && !cond.NotEmpty && cond.Max == nil && cond.Min == nil {
+		return fmt.Errorf("condition on %s has no operator", cond.Field)
+	}
+	if cond.Max != nil || cond.Min != nil {
+		// Resolve the field on an empty table and check to learn its type
+		switch resolve(&PolicySubject{Table: &GetTableResponse{}}, &Check{}).(type) {
+		case int, []string:
+		default:
+			return fmt.Errorf("max and min need a number or list field, and %s is neither", cond.Field)
+		}
+	}
+	return nil
+}
+
+// failure Describes why `value` fails the condition, or returns "" if it
+// holds.
+func (cond PolicyCondition) failure(value interface{}) string {
+	var values []string
+	size, sized := 0, false
+	switch v := value.(type) {
+	case []string:
+		values, size, sized = v, len(v), true
+	case int:
+		values, size, sized = []string{fmt.Sprint(v)}, v, true
+	default:
+		values = []string{fmt.Sprint(v)}
+	}
+	shown := formatDiffValue(value)
+
+	if cond.Equals != nil && !policyAnyOf(values, []string{*cond.Equals}) {
+		return fmt.Sprintf("%s is %s, want %q", cond.Field, shown, *cond.Equals)
+	}
+	if cond.OneOf != nil && !policyAnyOf(values, cond.OneOf) {
+		return fmt.Sprintf("%s is %s, want one of %s", cond.Field, shown, formatDiffValue(cond.OneOf))
+	}
+	if cond.NotEmpty && policyEmpty(value) {
+		return fmt.Sprintf("%s is empty", cond.Field)
+	}
+	if cond.Max != nil && sized && size > *cond.Max {
+		return fmt.Sprintf("%s is %d, want at most %d", cond.Field, size, *cond.Max)
+	}
+	if cond.Min != nil && sized && size < *cond.Min {
+		return fmt.Sprintf("%s is %d, want at least %d", cond.Field, size, *cond.Min)
+	}
+	return ""
+}
+
+func policyAnyOf(values []string, wanted []string) bool {
+	for _, value := range values {
+		for _, want := range wanted {
+			if value == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func policyEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case int:
+		return v == 0
+	case []string:
+		return len(v) == 0
+	}
+	return value == nil
+}
@@ -0,0 +1,194 @@
+package anomalo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPolicySubjects() []*PolicySubject {
+	channels := map[int]NotificationChannel{
+		1: {ID: 1, ChannelType: "pagerduty"},
+		2: {ID: 2, ChannelType: "slack"},
+	}
+	return []*PolicySubject{
+		{
+			Table: &GetTableResponse{ID: 5, FullName: "wh.sales.orders", Monitored: true,
+				Config: TableConfig{NotificationChannelID: 2, DisabledQualityCheckIds: []int{1, 2, 3}}},
+			Checks: []Check{
+				{CheckStaticID: 11, Config: CheckConfig{Metadata: CheckMetadata{PriorityLevel: "high"}}},
+				{CheckStaticID: 12, AdditionalNotificationChannelID: 1, Config: CheckConfig{Metadata: CheckMetadata{PriorityLevel: "high"}}},
+				{CheckStaticID: 13, Config: CheckConfig{Metadata: CheckMetadata{PriorityLevel: "low"}}},
+			},
+			Channels: channels,
+		},
+		{
+			Table:    &GetTableResponse{ID: 6, FullName: "wh.sales.refunds", Monitored: true},
+			Channels: channels,
+		},
+	}
+}
+
+func TestEvaluatePolicies(t *testing.T) {
+	report := EvaluatePolicies(testPolicySubjects(), []PolicyRule{
+		RequireNotificationChannel("notify"),
+		RequireChannelTypeForPriority("high-pagerduty", "high", "pagerduty"),
+		MaxDisabledQualityChecks("disabled", 2),
+	})
+	assert.Equal(t, `2 table(s), 3 violation(s)
+error: wh.sales.orders check 11: [high-pagerduty] high check routes to channel 2 (slack), want pagerduty
+error: wh.sales.orders: [disabled] 3 quality checks are disabled, want at most 2
+error: wh.sales.refunds: [notify] monitored table has no notification channel`, report.String())
+}
+
+const testPolicyRules = `
+rules:
+  - id: high-pagerduty
+    description: High priority checks must route to PagerDuty
+    scope: check
+    where:
+      - field: priority_level
+        equals: high
+    require:
+      - field: notification_channel_type
+        equals: pagerduty
+  - id: disabled
+    severity: warning
+    scope: table
+    where:
+      - field: monitored
+        equals: "true"
+    require:
+      - field: disabled_quality_checks
+        max: 2
+      - field: check_cadence_type
+        one_of: [daily, hourly]
+`
+
+func TestParsePolicyRules(t *testing.T) {
+	rules, err := ParsePolicyRules([]byte(testPolicyRules))
+	assert.Nil(t, err)
+	report := EvaluatePolicies(testPolicySubjects(), rules)
+	assert.Equal(t, `2 table(s), 3 violation(s)
+error: wh.sales.orders check 11: [high-pagerduty] notification_channel_type is ["slack"], want "pagerduty"
+warning: wh.sales.orders: [disabled] disabled_quality_checks is 3, want at most 2
+warning: wh.sales.refunds: [disabled] check_cadence_type is "", want one of ["daily" "hourly"]`, report.String())
+}
+
+func TestPolicyTableChannelWithAdditionalChannel(t *testing.T) {
+	subject := &PolicySubject{
+		Table: &GetTableResponse{ID: 5, FullName: "wh.sales.orders", Monitored: true,
+			Config: TableConfig{NotificationChannelID: 1}},
+		Checks: []Check{
+			{CheckStaticID: 11, AdditionalNotificationChannelID: 2, Labels: []*Label{nil, {Name: "finance"}},
+				Config: CheckConfig{Metadata: CheckMetadata{PriorityLevel: "high"}}},
+		},
+		Channels: testPolicySubjects()[0].Channels,
+	}
+	assert.Equal(t, []NotificationChannel{{ID: 1, ChannelType: "pagerduty"}, {ID: 2, ChannelType: "slack"}},
+		subject.CheckChannels(&subject.Checks[0]))
+
+	rules, err := ParsePolicyRules([]byte(testPolicyRules + `
+  - id: finance
+    scope: check
+    require:
+      - field: labels
+        equals: finance
+`))
+	assert.Nil(t, err)
+	rules = append(rules, RequireChannelTypeForPriority("high-pagerduty", "high", "pagerduty"))
+	report := EvaluatePolicies([]*PolicySubject{subject}, rules)
+	assert.Equal(t, `1 table(s), 1 violation(s)
+warning: wh.sales.orders: [disabled] check_cadence_type is "", want one of ["daily" "hourly"]`, report.String())
+}
+
+func TestParsePolicyRulesErrors(t *testing.T) {
+	for rules, want := range map[string]string{
+		"rules:\n  - id: a\n    scope: table\n    require:\n      - field: nope\n        not_empty: true\n":     `rule a: unknown table field "nope"`,
+		"rules:\n  - id: a\n    scope: table\n    require:\n      - field: full_name\n":                         "rule a: condition on full_name has no operator",
+		"rules:\n  - id: a\n    scope: table\n    require:\n      - field: full_name\n        max: 1\n":         "rule a: max and min need a number or list field, and full_name is neither",
+		"rules:\n  - id: a\n    scope: view\n    require:\n      - field: full_name\n        not_empty: true\n": `rule a: scope must be table or check, not "view"`,
+		"rules:\n  - id: a\n    scope: table\n":                                                                 "rule a: no require conditions",
+	} {
+		_, err := ParsePolicyRules([]byte(rules))
+		assert.EqualError(t, err, want)
+	}
+	_, err := ParsePolicyRules([]byte("rules:\n  - scope: table\n"))
+	assert.EqualError(t, err, "rule 0: rule has no id")
+	_, err = ParsePolicyRules([]byte("rules:\n  - id: a\n    scopes: table\n"))
+	assert.NotNil(t, err)
+}
+
+func TestPolicyReportSARIF(t *testing.T) {
+	report := EvaluatePolicies(testPolicySubjects()[:1], []PolicyRule{
+		RequireChannelTypeForPriority("high-pagerduty", "high", "pagerduty"),
+	})
+	var b strings.Builder
+	assert.Nil(t, report.Write(&b, PolicyFormatSARIF))
+
+	var log map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(b.String()), &log))
+	assert.Equal(t, "2.1.0", log["version"])
+	run := log["runs"].([]interface{})[0].(map[string]interface{})
+	results := run["results"].([]interface{})
+	assert.Len(t, results, 1)
+	assert.Equal(t, map[string]interface{}{
+		"ruleId":    "high-pagerduty",
+		"ruleIndex": float64(0),
+		"level":     "error",
+		"message":   map[string]interface{}{"text": "high check routes to channel 2 (slack), want pagerduty"},
+		"locations": []interface{}{map[string]interface{}{"logicalLocations": []interface{}{map[string]interface{}{
+			"name": "check 11", "fullyQualifiedName": "wh.sales.orders/checks/11", "kind": "resource",
+		}}}},
+	}, results[0])
+
+	b.Reset()
+	assert.Nil(t, report.Write(&b, PolicyFormatJSON))
+	assert.Contains(t, b.String(), `"check_static_id": 11`)
+	assert.NotNil(t, report.Write(&b, "xml"))
+}
+
+func TestLintWorkspace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/list_notification_channels":
+			w.Write([]byte(`{"notification_channels": [{"id": 2, "channel_type": "slack"}]}`))
+		case "/api/public/v1/list_warehouses":
+			w.Write([]byte(`{"warehouses": [{"id": 1}]}`))
+		case "/api/public/v1/list_tables":
+			w.Write([]byte(`{"tables": [
+				{"id": 6, "full_name": "wh.b", "monitored": true},
+				{"id": 7, "full_name": "wh.c"},
+				{"id": 5, "full_name": "wh.a", "monitored": true},
+				{"id": 8, "full_name": "wh.d", "monitored": true}
+			]}`))
+		case "/api/public/v1/get_table_information":
+			switch r.URL.Query().Get("table_id") {
+			case "5":
+				w.Write([]byte(`{"id": 5, "full_name": "wh.a", "monitored": true}`))
+			case "6":
+				w.Write([]byte(`{"id": 6, "full_name": "wh.b", "monitored": true, "config": {"notification_channel_id": 2}}`))
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		case "/api/public/v1/get_checks_for_table":
+			w.Write([]byte(`{"checks": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := &Client{Host: server.URL}
+
+	report, err := client.LintWorkspace([]PolicyRule{RequireNotificationChannel("notify")}, BulkOptions{MaxRetries: -1})
+	var bulkErr *BulkError
+	assert.True(t, errors.As(err, &bulkErr))
+	assert.Equal(t, `2 table(s), 1 violation(s)
+error: wh.a: [notify] monitored table has no notification channel
+unread: wh.d`, report.String())
+}
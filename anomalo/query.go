@@ -0,0 +1,138 @@
+package anomalo
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// encodeQuery Encodes a request struct as URL query parameters.
+//
+// Field names come from the `url` struct tag, falling back to the `json` tag
+// so that most request structs need no extra annotations. A tag of "-" skips
+// the field, and the `omitempty` option skips zero values.
+//
+// Values are encoded as follows:
+//   - Slices and arrays become repeated keys, e.g. `check_id=1&check_id=2`.
+//   - Integers are printed exactly, and floats are never printed in exponent form.
+//   - Booleans become `true` or `false`.
+//   - time.Time values are formatted as RFC3339.
+//   - Nested structs are flattened as `parent[child]`.
+//   - Nil pointers are skipped.
+func encodeQuery(v interface{}) (url.Values, error) {
+	values := url.Values{}
+	if v == nil {
+		return values, nil
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return values, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("query parameters must be a struct, got %s", rv.Type())
+	}
+	if err := encodeStruct(values, "", rv); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func encodeStruct(values url.Values, prefix string, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, omitEmpty := queryFieldName(field)
+		if name == "-" {
+			continue
+		}
+		if prefix != "" {
+			name = fmt.Sprintf("%s[%s]", prefix, name)
+		}
+		fv := rv.Field(i)
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+		if err := encodeValue(values, name, fv); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func queryFieldName(field reflect.StructField) (string, bool) {
+	tag, ok := field.Tag.Lookup("url")
+	if !ok {
+		tag, ok = field.Tag.Lookup("json")
+	}
+	if !ok {
+		return field.Name, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, strings.Contains(opts, "omitempty")
+}
+
+func encodeValue(values url.Values, name string, fv reflect.Value) error {
+	for fv.Kind() == reflect.Pointer || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+
+	if fv.Type() == timeType {
+		values.Add(name, fv.Interface().(time.Time).Format(time.RFC3339))
+		return nil
+	}
+	if fv.Type().Implements(textMarshalerType) {
+		text, err := fv.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		values.Add(name, string(text))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		values.Add(name, fv.String())
+	case reflect.Bool:
+		values.Add(name, strconv.FormatBool(fv.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		values.Add(name, strconv.FormatInt(fv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		values.Add(name, strconv.FormatUint(fv.Uint(), 10))
+	case reflect.Float32:
+		values.Add(name, strconv.FormatFloat(fv.Float(), 'f', -1, 32))
+	case reflect.Float64:
+		values.Add(name, strconv.FormatFloat(fv.Float(), 'f', -1, 64))
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < fv.Len(); i++ {
+			if err := encodeValue(values, name, fv.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		return encodeStruct(values, name, fv)
+	default:
+		return fmt.Errorf("unsupported query parameter type %s", fv.Type())
+	}
+	return nil
+}
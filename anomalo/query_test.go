@@ -0,0 +1,123 @@
+package anomalo
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type queryTestFilter struct {
+	Kind string `url:"kind"`
+}
+
+type queryTestRequest struct {
+	TableID     int64           `json:"table_id,omitempty"`
+	CheckIDs    []int           `json:"check_ids,omitempty"`
+	Statuses    []string        `url:"status,omitempty"`
+	Force       bool            `json:"force"`
+	Threshold   float64         `json:"threshold,omitempty"`
+	Since       time.Time       `json:"since,omitempty"`
+	WarehouseID *int            `json:"warehouse_id,omitempty"`
+	Filter      queryTestFilter `json:"filter"`
+	Ignored     string          `json:"-"`
+}
+
+func TestEncodeQuery(t *testing.T) {
+	warehouseID := 7
+	values, err := encodeQuery(&queryTestRequest{
+		TableID:     9007199254740993, // Not representable as a float64
+		CheckIDs:    []int{1, 2, 3},
+		Statuses:    []string{"fail", "error"},
+		Threshold:   12345678.5,
+		Since:       time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC),
+		WarehouseID: &warehouseID,
+		Filter:      queryTestFilter{Kind: "custom"},
+		Ignored:     "ignored",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "check_ids=1&check_ids=2&check_ids=3&filter%5Bkind%5D=custom&force=false&"+
+		"since=2023-04-05T06%3A07%3A08Z&status=fail&status=error&table_id=9007199254740993&"+
+		"threshold=12345678.5&warehouse_id=7", values.Encode())
+}
+
+func TestEncodeQueryOmitEmpty(t *testing.T) {
+	values, err := encodeQuery(&queryTestRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, "filter%5Bkind%5D=&force=false", values.Encode())
+
+	values, err = encodeQuery((*queryTestRequest)(nil))
+	assert.Nil(t, err)
+	assert.Empty(t, values)
+}
+
+func TestEncodeQueryRejectsNonStruct(t *testing.T) {
+	_, err := encodeQuery([]int{1})
+	assert.NotNil(t, err)
+}
+
+func TestGetParametersLargeIDs(t *testing.T) {
+	server := setupServer(
+		t,
+		"get_table_information?table_id=12345678901&warehouse_id=98765432109",
+		`{"id": 12345678901}`,
+		http.StatusOK,
+	)
+	defer server.Close()
+
+	client := Client{Host: server.URL}
+	resp, err := client.GetTableInformationFromRequest(GetTableInformationRequest{
+		WarehouseID: 98765432109,
+		TableID:     12345678901,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 12345678901, resp.ID)
+}
+
+func TestQueryListFilterEndpoints(t *testing.T) {
+	server := setupServer(
+		t,
+		"get_check_runs?check_ids=3&check_ids=12345678901&interval_id=9&table_id=5&triage_status=untriaged",
+		`{"check_runs": []}`,
+		http.StatusOK,
+	)
+	client := &Client{Host: server.URL}
+	_, err := client.GetCheckRuns(GetCheckRunsRequest{
+		TableID:      5,
+		IntervalID:   9,
+		CheckIDs:     []int{3, 12345678901},
+		TriageStatus: TriageStatusUntriaged,
+	})
+	assert.Nil(t, err)
+	server.Close()
+
+	// An empty list filter is left out rather than sent as an empty value
+	server = setupServer(t, "get_check_runs?interval_id=9&table_id=5", `{"check_runs": []}`, http.StatusOK)
+	client = &Client{Host: server.URL}
+	_, err = client.GetCheckRuns(GetCheckRunsRequest{TableID: 5, IntervalID: 9, CheckIDs: []int{}})
+	assert.Nil(t, err)
+	server.Close()
+
+	server = setupServer(
+		t,
+		"get_table_intervals?end=2023-02-01T00%3A00%3A00-08%3A00&start=2023-01-01T00%3A00%3A00-08%3A00&table_id=5",
+		`{"intervals": []}`,
+		http.StatusOK,
+	)
+	client = &Client{Host: server.URL}
+	pacific := time.FixedZone("PST", -8*60*60)
+	_, err = client.ListIntervals(ListIntervalsRequest{
+		TableID: 5,
+		Start:   time.Date(2023, 1, 1, 0, 0, 0, 0, pacific),
+		End:     time.Date(2023, 2, 1, 0, 0, 0, 0, pacific),
+	})
+	assert.Nil(t, err)
+	server.Close()
+
+	server = setupServer(t, "list_tables?warehouse_id=98765432109", `{"tables": []}`, http.StatusOK)
+	defer server.Close()
+	client = &Client{Host: server.URL}
+	_, err = client.ListTables(ListTablesRequest{WarehouseID: 98765432109})
+	assert.Nil(t, err)
+}
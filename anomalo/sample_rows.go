@@ -0,0 +1,165 @@
+package anomalo
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// SampleRowsKind Selects which sample rows of a check run to fetch.
+type SampleRowsKind string
+
+const (
+	// SampleRowsBad Rows that failed the check.
+	SampleRowsBad SampleRowsKind = "bad"
+	// SampleRowsGood Rows that passed the check.
+	SampleRowsGood SampleRowsKind = "good"
+)
+
+// ErrNoSampleRows Returned when a check run has no sample rows of the
+// requested kind.
+var ErrNoSampleRows = errors.New("check run has no sample rows")
+
+// SampleRows A CSV of sample rows. Each row maps column names from Header to
+// values.
+type SampleRows struct {
+	Header []string
+	Rows   []map[string]string
+}
+
+// SampleRowsReader Streams the records of a sample rows CSV. Header holds the
+// column names, and Read returns the following records one at a time. Close
+// must be called once done.
+type SampleRowsReader struct {
+	*csv.Reader
+	Header []string
+
+	body io.ReadCloser
+}
+
+func (r *SampleRowsReader) Close() error {
+	return r.body.Close()
+}
+
+// OpenSampleRows Starts downloading the sample rows of `run` and returns a
+// reader over its records. Downloads larger than Client.MaxResponseBytes fail
+// with an *http.MaxBytesError partway through.
+//
+// The sample rows URL may point at Anomalo or at external storage, so the
+// client's token is only sent when the URL is on the same host as
+// Client.Host.
+func (c *Client) OpenSampleRows(ctx context.Context, run *CheckRun, kind SampleRowsKind) (*SampleRowsReader, error) {
+	body, err := c.openSampleRows(ctx, run, kind)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(body)
+	header, err := reader.Read()
+	if err == io.EOF {
+		header, err = nil, nil // An empty CSV has no header and no rows
+	}
+	if err != nil {
+		closeBody(body)
+		return nil, fmt.Errorf("unable to read sample rows header. %w", err)
+	}
+	return &SampleRowsReader{Reader: reader, Header: header, body: body}, nil
+}
+
+// FetchSampleRows Downloads and parses the sample rows of `run`. See
+// OpenSampleRows to stream large CSVs instead.
+func (c *Client) FetchSampleRows(ctx context.Context, run *CheckRun, kind SampleRowsKind) (*SampleRows, error) {
+	reader, err := c.OpenSampleRows(ctx, run, kind)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	rows := &SampleRows{Header: reader.Header}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read sample rows. %w", err)
+		}
+		row := make(map[string]string, len(record))
+		for i, value := range record {
+			row[reader.Header[i]] = value
+		}
+		rows.Rows = append(rows.Rows, row)
+	}
+}
+
+// DownloadSampleRows Saves the sample rows CSV of `run` to `path` unchanged.
+// The file is removed if the download fails.
+func (c *Client) DownloadSampleRows(ctx context.Context, run *CheckRun, kind SampleRowsKind, path string) error {
+	body, err := c.openSampleRows(ctx, run, kind)
+	if err != nil {
+		return err
+	}
+	defer closeBody(body)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("unable to download sample rows to %s. %w", path, err)
+	}
+	return nil
+}
+
+func (c *Client) openSampleRows(ctx context.Context, run *CheckRun, kind SampleRowsKind) (io.ReadCloser, error) {
+	rawURL := run.Results.SampleRowsBadCsvUrl
+	if kind == SampleRowsGood {
+		rawURL = run.Results.SampleRowsGoodCsvUrl
+	} else if kind != SampleRowsBad {
+		return nil, fmt.Errorf("unknown sample rows kind %q", kind)
+	}
+	if rawURL == "" {
+		return nil, fmt.Errorf("%w: no %s rows for check run %d", ErrNoSampleRows, kind, run.CheckRunID)
+	}
+
+	host, err := url.Parse(c.Host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid host %q. %w", c.Host, err)
+	}
+	target, err := host.Parse(rawURL) // Relative URLs are relative to Client.Host
+	if err != nil {
+		return nil, fmt.Errorf("invalid sample rows URL %q. %w", rawURL, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if target.Scheme == host.Scheme && target.Host == host.Host {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := c.getClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download sample rows. %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer closeBody(resp.Body)
+		apiErr := &APIError{StatusCode: resp.StatusCode, RetryAfter: resp.Header.Get("Retry-After")}
+		bodyBytes, err := io.ReadAll(io.LimitReader(resp.Body, c.maxResponseBytes()))
+		if err != nil {
+			return nil, fmt.Errorf("response code %d. unable to read response body. got %w", resp.StatusCode, err)
+		}
+		apiErr.Body = string(bodyBytes)
+		return nil, apiErr
+	}
+	return http.MaxBytesReader(nil, resp.Body, c.maxResponseBytes()), nil
+}
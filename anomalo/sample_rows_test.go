@@ -0,0 +1,79 @@
+package anomalo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleCsv = "id,amount\n1,10\n2,\"1,000\"\n"
+
+func TestFetchSampleRows(t *testing.T) {
+	anomalo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/bad.csv", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		w.Write([]byte(sampleCsv))
+	}))
+	defer anomalo.Close()
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "", r.Header.Get("Authorization"))
+		w.Write([]byte("id\n3\n"))
+	}))
+	defer storage.Close()
+
+	client := &Client{Host: anomalo.URL, Token: "token"}
+	run := &CheckRun{CheckRunID: 9, Results: CheckRunResults{
+		SampleRowsBadCsvUrl:  "/bad.csv",
+		SampleRowsGoodCsvUrl: storage.URL + "/good.csv?signature=abc",
+	}}
+
+	bad, err := client.FetchSampleRows(context.Background(), run, SampleRowsBad)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"id", "amount"}, bad.Header)
+	assert.Equal(t, []map[string]string{{"id": "1", "amount": "10"}, {"id": "2", "amount": "1,000"}}, bad.Rows)
+
+	good, err := client.FetchSampleRows(context.Background(), run, SampleRowsGood)
+	assert.Nil(t, err)
+	assert.Equal(t, []map[string]string{{"id": "3"}}, good.Rows)
+
+	reader, err := client.OpenSampleRows(context.Background(), run, SampleRowsBad)
+	assert.Nil(t, err)
+	record, err := reader.Read()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "10"}, record)
+	assert.Nil(t, reader.Close())
+
+	path := filepath.Join(t.TempDir(), "bad.csv")
+	assert.Nil(t, client.DownloadSampleRows(context.Background(), run, SampleRowsBad, path))
+	contents, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, sampleCsv, string(contents))
+
+	_, err = client.FetchSampleRows(context.Background(), &CheckRun{}, SampleRowsBad)
+	assert.True(t, errors.Is(err, ErrNoSampleRows))
+}
+
+func TestFetchSampleRowsSizeLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleCsv))
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL, MaxResponseBytes: 12}
+	run := &CheckRun{Results: CheckRunResults{SampleRowsBadCsvUrl: "/bad.csv"}}
+
+	_, err := client.FetchSampleRows(context.Background(), run, SampleRowsBad)
+	var maxBytesErr *http.MaxBytesError
+	assert.True(t, errors.As(err, &maxBytesErr))
+
+	path := filepath.Join(t.TempDir(), "bad.csv")
+	assert.NotNil(t, client.DownloadSampleRows(context.Background(), run, SampleRowsBad, path))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
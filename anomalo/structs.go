@@ -1,10 +1,15 @@
 package anomalo
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type PingResponse struct {
 	Ping string `json:"ping"`
 	User string `json:"user"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type Label struct {
@@ -20,129 +25,150 @@ type GetTableInformationRequest struct {
 	TableID     int    `json:"table_id,omitempty"`
 }
 
+// UserRef The user that created or last edited an object.
+type UserRef struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type TableConfig struct {
+	TableID                   int       `json:"table_id,omitempty"`
+	CheckCadenceType          string    `json:"check_cadence_type,omitempty"`
+	Definition                string    `json:"definition,omitempty"`
+	TimeColumnType            string    `json:"time_column_type,omitempty"`
+	NotifyAfter               string    `json:"notify_after,omitempty"`
+	NotificationChannelID     int       `json:"notification_channel_id,omitempty"`
+	TimeColumns               []string  `json:"time_columns,omitempty"`
+	FreshAfter                string    `json:"fresh_after,omitempty"`
+	CheckCadenceRunAtDuration string    `json:"check_cadence_run_at_duration,omitempty"`
+	IntervalSkipExpr          string    `json:"interval_skip_expr,omitempty"`
+	AlwaysAlertOnErrors       bool      `json:"always_alert_on_errors,omitempty"`
+	DisabledQualityCheckIds   []int     `json:"disabled_quality_check_ids,omitempty"`
+	Created                   time.Time `json:"created,omitempty"`
+	CreatedBy                 UserRef   `json:"created_by,omitempty"`
+	LastEditedAt              string    `json:"last_edited_at,omitempty"`
+	LastEditedBy              UserRef   `json:"last_edited_by,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type TableRecentStatus struct {
+	RecentIntervals []Interval `json:"recent_intervals,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type WarehouseRef struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
 type GetTableResponse struct {
-	Description         string `json:"description,omitempty"`
-	FullName            string `json:"full_name,omitempty"`
-	ID                  int    `json:"id,omitempty"`
-	Monitored           bool   `json:"monitored,omitempty"`
-	NotificationChannel struct {
-		ChannelType string `json:"channel_type,omitempty"`
-		Description string `json:"description,omitempty"`
-		ID          int    `json:"id,omitempty"`
-	} `json:"notification_channel,omitempty"`
-	RecentStatus struct {
-		RecentIntervals []struct {
-			IntervalID           int       `json:"interval_id,omitempty"`
-			LatestRunChecksJobID string    `json:"latest_run_checks_job_id,omitempty"`
-			Status               string    `json:"status,omitempty"`
-			StatusDisplay        string    `json:"status_display,omitempty"`
-			TimePeriodEnd        time.Time `json:"time_period_end,omitempty"`
-			TimePeriodStart      time.Time `json:"time_period_start,omitempty"`
-		} `json:"recent_intervals,omitempty"`
-	} `json:"recent_status,omitempty"`
-	Warehouse struct {
-		ID   int    `json:"id,omitempty"`
-		Name string `json:"name,omitempty"`
-	} `json:"warehouse,omitempty"`
-	Config struct {
-		TableID                   int       `json:"table_id,omitempty"`
-		CheckCadenceType          string    `json:"check_cadence_type,omitempty"`
-		Definition                string    `json:"definition,omitempty"`
-		TimeColumnType            string    `json:"time_column_type,omitempty"`
-		NotifyAfter               string    `json:"notify_after,omitempty"`
-		NotificationChannelID     int       `json:"notification_channel_id,omitempty"`
-		TimeColumns               []string  `json:"time_columns,omitempty"`
-		FreshAfter                string    `json:"fresh_after,omitempty"`
-		CheckCadenceRunAtDuration string    `json:"check_cadence_run_at_duration,omitempty"`
-		IntervalSkipExpr          string    `json:"interval_skip_expr,omitempty"`
-		AlwaysAlertOnErrors       bool      `json:"always_alert_on_errors,omitempty"`
-		DisabledQualityCheckIds   []int     `json:"disabled_quality_check_ids,omitempty"`
-		Created                   time.Time `json:"created,omitempty"`
-		CreatedBy                 struct {
-			ID   int    `json:"id,omitempty"`
-			Name string `json:"name,omitempty"`
-		} `json:"created_by,omitempty"`
-		LastEditedAt string `json:"last_edited_at,omitempty"`
-		LastEditedBy struct {
-			ID   int    `json:"id,omitempty"`
-			Name string `json:"name,omitempty"`
-		} `json:"last_edited_by,omitempty"`
-	} `json:"config,omitempty"`
+	Description         string              `json:"description,omitempty"`
+	FullName            string              `json:"full_name,omitempty"`
+	ID                  int                 `json:"id,omitempty"`
+	Monitored           bool                `json:"monitored,omitempty"`
+	NotificationChannel NotificationChannel `json:"notification_channel,omitempty"`
+	RecentStatus        TableRecentStatus   `json:"recent_status,omitempty"`
+	Warehouse           WarehouseRef        `json:"warehouse,omitempty"`
+	Config              TableConfig         `json:"config,omitempty"`
+	Labels              []*Label            `json:"labels,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
+// ConfigureTableRequest Fields other than TableID are Optional: unset fields
+// are not sent, Null clears a setting, and Set changes it. A Null
+// CheckCadenceType stops scheduled checks on the table.
 type ConfigureTableRequest struct {
-	TableID                   int      `json:"table_id,omitempty"`
-	CheckCadenceType          *string  `json:"check_cadence_type"`
-	Definition                string   `json:"definition,omitempty"`
-	TimeColumnType            string   `json:"time_column_type,omitempty"`
-	NotifyAfter               string   `json:"notify_after,omitempty"`
-	NotificationChannelID     int      `json:"notification_channel_id,omitempty"`
-	TimeColumns               []string `json:"time_columns,omitempty"`
-	FreshAfter                string   `json:"fresh_after,omitempty"`
-	CheckCadenceRunAtDuration string   `json:"check_cadence_run_at_duration,omitempty"`
-	IntervalSkipExpr          string   `json:"interval_skip_expr,omitempty"`
-	AlwaysAlertOnErrors       bool     `json:"always_alert_on_errors,omitempty"`
-	DisabledQualityCheckIds   []int    `json:"disabled_quality_check_ids,omitempty"`
+	TableID                   int                `json:"table_id,omitempty"`
+	CheckCadenceType          Optional[string]   `json:"check_cadence_type"`
+	Definition                Optional[string]   `json:"definition"`
+	TimeColumnType            Optional[string]   `json:"time_column_type"`
+	NotifyAfter               Optional[string]   `json:"notify_after"`
+	NotificationChannelID     Optional[int]      `json:"notification_channel_id"`
+	TimeColumns               Optional[[]string] `json:"time_columns"`
+	FreshAfter                Optional[string]   `json:"fresh_after"`
+	CheckCadenceRunAtDuration Optional[string]   `json:"check_cadence_run_at_duration"`
+	IntervalSkipExpr          Optional[string]   `json:"interval_skip_expr"`
+	AlwaysAlertOnErrors       Optional[bool]     `json:"always_alert_on_errors"`
+	DisabledQualityCheckIds   Optional[[]int]    `json:"disabled_quality_check_ids"`
 }
 
 type ConfigureTableResponse struct {
-	ID            int       `json:"id,omitempty"`
-	Created       time.Time `json:"created,omitempty"`
-	Modified      time.Time `json:"modified,omitempty"`
-	SchemaID      int       `json:"schema_id,omitempty"`
-	Name          string    `json:"name,omitempty"`
-	Definition    string    `json:"definition,omitempty"`
-	LastRefreshed time.Time `json:"last_refreshed,omitempty"`
-	Config        struct {
-	} `json:"config,omitempty"`
-	UpdateModified bool `json:"update_modified,omitempty"`
+	ID             int         `json:"id,omitempty"`
+	Created        time.Time   `json:"created,omitempty"`
+	Modified       time.Time   `json:"modified,omitempty"`
+	SchemaID       int         `json:"schema_id,omitempty"`
+	Name           string      `json:"name,omitempty"`
+	Definition     string      `json:"definition,omitempty"`
+	LastRefreshed  time.Time   `json:"last_refreshed,omitempty"`
+	Config         TableConfig `json:"config,omitempty"`
+	UpdateModified bool        `json:"update_modified,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type CheckMetadata struct {
+	CheckMessage     string `json:"check_message,omitempty"`
+	CheckMessageHTML string `json:"check_message_html,omitempty"`
+	CheckType        string `json:"check_type,omitempty"`
+	Description      string `json:"description,omitempty"`
+	IsSystemCheck    bool   `json:"is_system_check,omitempty"`
+	PriorityLevel    string `json:"priority_level,omitempty"`
+}
+
+// CheckConfig The configuration of a check. CheckID is only set on the
+// RunConfig of a CheckRun.
+type CheckConfig struct {
+	Metadata CheckMetadata          `json:"_metadata,omitempty"`
+	Check    string                 `json:"check,omitempty"`
+	CheckID  int                    `json:"check_id,omitempty"`
+	Params   map[string]interface{} `json:"params,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type Check struct {
-	CheckID       int    `json:"check_id,omitempty"`
-	CheckStaticID int    `json:"check_static_id,omitempty"`
-	Ref           string `json:"ref,omitempty"`
-	CheckType     string `json:"check_type,omitempty"`
-	Config        struct {
-		Metadata struct {
-			CheckMessage     string `json:"check_message,omitempty"`
-			CheckMessageHTML string `json:"check_message_html,omitempty"`
-			CheckType        string `json:"check_type,omitempty"`
-			Description      string `json:"description,omitempty"`
-			IsSystemCheck    bool   `json:"is_system_check,omitempty"`
-			PriorityLevel    string `json:"priority_level,omitempty"`
-		} `json:"_metadata,omitempty"`
-		Check  string                 `json:"check,omitempty"`
-		Params map[string]interface{} `json:"params,omitempty"`
-	} `json:"config,omitempty"`
-	Created   time.Time `json:"created,omitempty"`
-	CreatedBy struct {
-		ID   int    `json:"id,omitempty"`
-		Name string `json:"name,omitempty"`
-	} `json:"created_by,omitempty"`
-	LastEditedAt string `json:"last_edited_at,omitempty"`
-	LastEditedBy struct {
-		ID   int    `json:"id,omitempty"`
-		Name string `json:"name,omitempty"`
-	} `json:"last_edited_by,omitempty"`
-	TriageStatus                    string `json:"triage_status,omitempty"`
-	AdditionalNotificationChannelID int    `json:"additional_notification_channel_id,omitempty"`
+	CheckID                         int         `json:"check_id,omitempty"`
+	CheckStaticID                   int         `json:"check_static_id,omitempty"`
+	Ref                             string      `json:"ref,omitempty"`
+	CheckType                       string      `json:"check_type,omitempty"`
+	Config                          CheckConfig `json:"config,omitempty"`
+	Created                         time.Time   `json:"created,omitempty"`
+	CreatedBy                       UserRef     `json:"created_by,omitempty"`
+	LastEditedAt                    string      `json:"last_edited_at,omitempty"`
+	LastEditedBy                    UserRef     `json:"last_edited_by,omitempty"`
+	TriageStatus                    string      `json:"triage_status,omitempty"`
+	AdditionalNotificationChannelID int         `json:"additional_notification_channel_id,omitempty"`
+	Labels                          []*Label    `json:"labels,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type getChecksRequest struct {
+	TableID int `json:"table_id"`
 }
 
 type GetChecksResponse struct {
 	Checks []Check `json:"checks,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type CreateCheckRequest struct {
 	CheckType string            `json:"check_type,omitempty"`
 	Params    map[string]string `json:"params,omitempty"`
 	TableID   int               `json:"table_id,omitempty"`
+	Ref       string            `json:"ref,omitempty"`
 }
 
 type CreateCheckResponse struct {
 	CheckID       int    `json:"check_id,omitempty"`
 	CheckRef      string `json:"ref,omitempty"`
 	CheckStaticId int    `json:"check_static_id,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type DeleteCheckRequest struct {
@@ -152,6 +178,55 @@ type DeleteCheckRequest struct {
 
 type DeleteCheckResponse struct {
 	DeletedCount int `json:"deleted_count,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+const (
+	LabelScopeCheck = "check"
+	LabelScopeTable = "table"
+)
+
+type ListLabelsResponse struct {
+	Labels []*Label `json:"labels,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// CreateLabelRequest Scope is one of the LabelScope constants, and restricts
+// what the label can be attached to.
+type CreateLabelRequest struct {
+	Name  string `json:"name,omitempty"`
+	Scope string `json:"scope,omitempty"`
+}
+
+type DeleteLabelRequest struct {
+	LabelID int `json:"label_id,omitempty"`
+}
+
+type DeleteLabelResponse struct {
+	DeletedCount int `json:"deleted_count,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type CheckLabelsRequest struct {
+	TableID  int   `json:"table_id,omitempty"`
+	CheckID  int   `json:"check_id,omitempty"`
+	LabelIDs []int `json:"label_ids,omitempty"`
+}
+
+type TableLabelsRequest struct {
+	TableID  int   `json:"table_id,omitempty"`
+	LabelIDs []int `json:"label_ids,omitempty"`
+}
+
+// LabelsResponse The labels on a check or table after labels were attached
+// or detached.
+type LabelsResponse struct {
+	Labels []*Label `json:"labels,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type RunChecksRequest struct {
@@ -164,64 +239,114 @@ type RunChecksRequest struct {
 	RespectDataFreshnessGate bool     `json:"respect_data_freshness_gate,omitempty"`
 }
 
+const (
+	RunStatusPass    = "pass"
+	RunStatusFail    = "fail"
+	RunStatusError   = "error"
+	RunStatusPending = "pending"
+	RunStatusSkipped = "skipped"
+)
+
+type Interval struct {
+	IntervalID                   int       `json:"interval_id,omitempty"`
+	LatestRunChecksJobID         string    `json:"latest_run_checks_job_id,omitempty"`
+	IntervalLatestCheckRunsToken string    `json:"interval_latest_check_runs_token,omitempty"`
+	Status                       string    `json:"status,omitempty"`
+	StatusDisplay                string    `json:"status_display,omitempty"`
+	TimePeriodEnd                time.Time `json:"time_period_end,omitempty"`
+	TimePeriodStart              time.Time `json:"time_period_start,omitempty"`
+}
+
+type CheckRunResults struct {
+	Errored              bool    `json:"errored,omitempty"`
+	EvaluatedMessage     string  `json:"evaluated_message,omitempty"`
+	ExceptionMsg         string  `json:"exception_msg,omitempty"`
+	ExceptionTraceback   string  `json:"exception_traceback,omitempty"`
+	HistoryMessage       string  `json:"history_message,omitempty"`
+	SampleRowsBadCsvUrl  string  `json:"sample_rows_bad_csv_url,omitempty"`
+	SampleRowsBadSql     string  `json:"sample_rows_bad_sql,omitempty"`
+	SampleRowsGoodCsvUrl string  `json:"sample_rows_good_csv_url,omitempty"`
+	SampleRowsGoodSql    string  `json:"sample_rows_good_sql,omitempty"`
+	Statistic            float32 `json:"statistic,omitempty"`
+	StatisticName        string  `json:"statistic_name,omitempty"`
+	Success              bool    `json:"success,omitempty"`
+}
+
+type CheckRun struct {
+	CheckID     int       `json:"check_id,omitempty"`
+	CheckRunID  int       `json:"check_run_id,omitempty"`
+	CompletedAt time.Time `json:"completed_at,omitempty"`
+	Created     time.Time `json:"created,omitempty"`
+	// CreatedBy Has no json tag, as in the original anonymous struct, so it
+	// is matched against a "CreatedBy" key rather than "created_by".
+	CreatedBy      UserRef
+	Labels         []*Label        `json:"labels,omitempty"`
+	LastEditedAt   time.Time       `json:"last_edited_at,omitempty"`
+	LastEditedBy   UserRef         `json:"last_edited_by,omitempty"`
+	Results        CheckRunResults `json:"results,omitempty"`
+	ResultsPending bool            `json:"results_pending,omitempty"`
+	RunConfig      CheckConfig     `json:"run_config,omitempty"`
+	TriageStatus   *string         `json:"triage_status,omitempty"`
+	Status         string          `json:"status,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// RunChecksInterval The interval a RunChecks call ran checks for. Kept as an
+// alias of Interval for code written against the old name.
+type RunChecksInterval = Interval
+
 type RunChecksResponse struct {
-	RunChecksJobId     string   `json:"run_checks_job_id,omitempty"`
-	RunChecksAllJobIds []string `json:"run_checks_all_job_ids,omitempty"`
-	TimeInterval       struct {
-		IntervalID                   int       `json:"interval_id,omitempty"`
-		LatestRunChecksJobId         string    `json:"latest_run_checks_job_id,omitempty"`
-		IntervalLatestCheckRunsToken string    `json:"interval_latest_check_runs_token,omitempty"`
-		Status                       string    `json:"status,omitempty"`
-		StatusDisplay                string    `json:"status_display,omitempty"`
-		TimePeriodEnd                time.Time `json:"time_period_end,omitempty"`
-		TimePeriodStart              time.Time `json:"time_period_start,omitempty"`
-	} `json:"time_interval,omitempty"`
-	CheckRuns          []struct {
-		CheckID        int       `json:"check_id,omitempty"`
-		CheckRunID     int       `json:"check_run_id,omitempty"`
-		CompletedAt    time.Time `json:"completed_at,omitempty"`
-		Created        time.Time `json:"created,omitempty"`
-		CreatedBy      struct {
-			ID   int    `json:"id,omitempty"`
-			Name string `json:"name,omitempty"`
-		}
-		Labels         []*Label  `json:"labels,omitempty"`
-		LastEditedAt   time.Time `json:"last_edited_at,omitempty"`
-		LastEditedBy   struct {
-			ID   int    `json:"id,omitempty"`
-			Name string `json:"name,omitempty"`
-		} `json:"last_edited_by,omitempty"`
-		Results        struct {
-			Errored              bool     `json:"errored,omitempty"`
-			EvaluatedMessage     string   `json:"evaluated_message,omitempty"`
-			ExceptionMsg         string   `json:"exception_msg,omitempty"`
-			ExceptionTraceback   string   `json:"exception_traceback,omitempty"`
-			HistoryMessage       string   `json:"history_message,omitempty"`
-			SampleRowsBadCsvUrl  string   `json:"sample_rows_bad_csv_url,omitempty"`
-			SampleRowsBadSql     string   `json:"sample_rows_bad_sql,omitempty"`
-			SampleRowsGoodCsvUrl string   `json:"sample_rows_good_csv_url,omitempty"`
-			SampleRowsGoodSql    string   `json:"sample_rows_good_sql,omitempty"`
-			Statistic            float32  `json:"statistic,omitempty"`
-			StatisticName        string   `json:"statistic_name,omitempty"`
-			Success              bool     `json:"success,omitempty"`
-		} `json:"results,omitempty"`
-		ResultsPending bool `json:"results_pending,omitempty"`
-		RunConfig      struct {
-			Metadata struct {
-				CheckMessage     string `json:"check_message,omitempty"`
-				CheckMessageHTML string `json:"check_message_html,omitempty"`
-				CheckType        string `json:"check_type,omitempty"`
-				Description      string `json:"description,omitempty"`
-				IsSystemCheck    bool   `json:"is_system_check,omitempty"`
-				PriorityLevel    string `json:"priority_level,omitempty"`
-			} `json:"_metadata,omitempty"`
-			Check   string                 `json:"check,omitempty"`
-			CheckID int                    `json:"check_id,omitempty"`
-			Params  map[string]interface{} `json:"params,omitempty"`
-		} `json:"run_config,omitempty"`
-		TriageStatus   *string `json:"triage_status,omitempty"`
-		Status         string  `json:"status,omitempty"`
-	} `json:"check_runs,omitempty"`
+	RunChecksJobId     string     `json:"run_checks_job_id,omitempty"`
+	RunChecksAllJobIds []string   `json:"run_checks_all_job_ids,omitempty"`
+	TimeInterval       Interval   `json:"time_interval,omitempty"`
+	CheckRuns          []CheckRun `json:"check_runs,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type ListIntervalsRequest struct {
+	TableID int `json:"table_id,omitempty"`
+	// Start & End Only intervals whose time period overlaps [Start, End) are returned.
+	Start time.Time `json:"start,omitempty"`
+	End   time.Time `json:"end,omitempty"`
+}
+
+type ListIntervalsResponse struct {
+	Intervals []Interval `json:"intervals,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type GetCheckRunsRequest struct {
+	TableID    int   `json:"table_id,omitempty"`
+	IntervalID int   `json:"interval_id,omitempty"`
+	CheckIDs   []int `json:"check_ids,omitempty"`
+	// TriageStatus Only return runs with this triage status. See the
+	// TriageStatus constants.
+	TriageStatus string `json:"triage_status,omitempty"`
+}
+
+const (
+	TriageStatusUntriaged    = "untriaged"
+	TriageStatusAcknowledged = "acknowledged"
+	TriageStatusExpected     = "expected"
+	TriageStatusResolved     = "resolved"
+)
+
+// UpdateTriageStatusRequest TriageStatus is one of the TriageStatus constants.
+// Note is optional, and is shown alongside the status in Anomalo.
+type UpdateTriageStatusRequest struct {
+	TableID      int    `json:"table_id,omitempty"`
+	CheckRunID   int    `json:"check_run_id,omitempty"`
+	TriageStatus string `json:"triage_status,omitempty"`
+	Note         string `json:"note,omitempty"`
+}
+
+type GetCheckRunsResponse struct {
+	CheckRuns []CheckRun `json:"check_runs,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type NotificationChannel struct {
@@ -232,6 +357,8 @@ type NotificationChannel struct {
 
 type GetNotificationChannelsResponse struct {
 	NotificationChannels []NotificationChannel `json:"notification_channels,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type Organization struct {
@@ -241,28 +368,95 @@ type Organization struct {
 
 type GetOrganizationsResponse struct {
 	Organizations []Organization `json:"organizations,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type changeOrganizationRequest struct {
+	ID int64 `json:"id,string"`
 }
 
 type ChangeOrganizationResponse struct {
 	ID int `json:"id,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type DiscoverNewWarehouseTablesResponse struct {
-    ID                        int       `json:"id,omitempty"`
-    Name                      string    `json:"name,omitempty"`
-    LastRefreshed             time.Time `json:"last_refreshed,omitempty"`
-    LastRefreshStarted        time.Time `json:"last_refresh_started,omitempty"`
-    LastPartialRefreshed      time.Time `json:"last_partial_refreshed,omitempty"`
-    LastPartialRefreshStarted time.Time `json:"last_partial_refresh_started,omitempty"`
+	ID                        int       `json:"id,omitempty"`
+	Name                      string    `json:"name,omitempty"`
+	LastRefreshed             time.Time `json:"last_refreshed,omitempty"`
+	LastRefreshStarted        time.Time `json:"last_refresh_started,omitempty"`
+	LastPartialRefreshed      time.Time `json:"last_partial_refreshed,omitempty"`
+	LastPartialRefreshStarted time.Time `json:"last_partial_refresh_started,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+const (
+	WarehouseTypeBigQuery  = "bigquery"
+	WarehouseTypeSnowflake = "snowflake"
+)
+
+type Warehouse struct {
+	ID                        int       `json:"id,omitempty"`
+	Name                      string    `json:"name,omitempty"`
+	WarehouseType             string    `json:"warehouse_type,omitempty"`
+	IsActive                  bool      `json:"is_active,omitempty"`
+	SchemaCrawlExclusionList  []string  `json:"schema_crawl_exclusion_list,omitempty"`
+	SchemaCrawlInclusionList  []string  `json:"schema_crawl_inclusion_list,omitempty"`
+	LastRefreshed             time.Time `json:"last_refreshed,omitempty"`
+	LastRefreshStarted        time.Time `json:"last_refresh_started,omitempty"`
+	LastPartialRefreshed      time.Time `json:"last_partial_refreshed,omitempty"`
+	LastPartialRefreshStarted time.Time `json:"last_partial_refresh_started,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type ListWarehousesResponse struct {
-	Warehouses []struct {
-		ID                       int      `json:"id,omitempty"`
-		Name                     string   `json:"name,omitempty"`
-		WarehouseType            string   `json:"warehouse_type,omitempty"`
-		IsActive                 bool     `json:"is_active,omitempty"`
-		SchemaCrawlExclusionList []string `json:"schema_crawl_exclusion_list,omitempty"`
-		SchemaCrawlInclusionList []string `json:"schema_crawl_inclusion_list,omitempty"`
-	} `json:"warehouses,omitempty"`
+	Warehouses []Warehouse `json:"warehouses,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// CreateWarehouseRequest The keys in Connection depend on WarehouseType, and
+// follow the connection settings in the Anomalo UI (e.g. `account`, `user`
+// and `private_key` for Snowflake, or `project_id` and
+// `service_account_json` for BigQuery).
+type CreateWarehouseRequest struct {
+	Name                     string                 `json:"name,omitempty"`
+	WarehouseType            string                 `json:"warehouse_type,omitempty"`
+	Connection               map[string]interface{} `json:"connection,omitempty"`
+	SchemaCrawlExclusionList []string               `json:"schema_crawl_exclusion_list,omitempty"`
+	SchemaCrawlInclusionList []string               `json:"schema_crawl_inclusion_list,omitempty"`
+}
+
+// UpdateWarehouseRequest Unset fields are left unchanged. Set a crawl list to
+// an empty list to clear it.
+type UpdateWarehouseRequest struct {
+	ID                       int                    `json:"-"`
+	Name                     Optional[string]       `json:"name"`
+	IsActive                 Optional[bool]         `json:"is_active"`
+	Connection               map[string]interface{} `json:"connection,omitempty"`
+	SchemaCrawlExclusionList Optional[[]string]     `json:"schema_crawl_exclusion_list"`
+	SchemaCrawlInclusionList Optional[[]string]     `json:"schema_crawl_inclusion_list"`
+}
+
+type Table struct {
+	ID          int    `json:"id,omitempty"`
+	FullName    string `json:"full_name,omitempty"`
+	WarehouseID int    `json:"warehouse_id,omitempty"`
+	Monitored   bool   `json:"monitored,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type ListTablesRequest struct {
+	WarehouseID int `json:"warehouse_id,omitempty"`
+}
+
+type ListTablesResponse struct {
+	Tables []Table `json:"tables,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
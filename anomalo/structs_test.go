@@ -0,0 +1,95 @@
+package anomalo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertRoundTrip(t *testing.T, v interface{}, wire string) {
+	assert.Nil(t, json.Unmarshal([]byte(wire), v))
+	encoded, err := json.Marshal(v)
+	assert.Nil(t, err)
+	assert.JSONEq(t, wire, string(encoded))
+}
+
+func TestCheckWireFormat(t *testing.T) {
+	assertRoundTrip(t, &Check{}, `{
+		"check_id": 1,
+		"check_static_id": 2,
+		"ref": "ref",
+		"check_type": "NullCheck",
+		"config": {
+			"_metadata": {"check_type": "NullCheck", "priority_level": "high"},
+			"check": "NullCheck",
+			"params": {"column_name": "id"}
+		},
+		"created": "2023-01-01T00:00:00Z",
+		"created_by": {"id": 3, "name": "creator"},
+		"last_edited_at": "2023-01-02T00:00:00Z",
+		"last_edited_by": {"id": 4, "name": "editor"}
+	}`)
+}
+
+func TestGetTableResponseWireFormat(t *testing.T) {
+	var table GetTableResponse
+	assertRoundTrip(t, &table, `{
+		"id": 5,
+		"full_name": "wh.schema.table",
+		"notification_channel": {"id": 6, "channel_type": "slack", "description": "#alerts"},
+		"recent_status": {"recent_intervals": [{
+			"interval_id": 7,
+			"status": "pass",
+			"time_period_start": "2023-01-01T00:00:00Z",
+			"time_period_end": "2023-01-02T00:00:00Z"
+		}]},
+		"warehouse": {"id": 8, "name": "wh"},
+		"config": {
+			"table_id": 5,
+			"check_cadence_type": "daily",
+			"time_columns": ["created_at"],
+			"created": "2023-01-01T00:00:00Z",
+			"created_by": {"id": 3, "name": "creator"},
+			"last_edited_by": {"id": 4, "name": "editor"}
+		}
+	}`)
+
+	req := table.Config.ToConfigureTableRequest()
+	assert.Equal(t, 5, req.TableID)
+	assert.Equal(t, Set("daily"), req.CheckCadenceType)
+	assert.Equal(t, Set([]string{"created_at"}), req.TimeColumns)
+	assert.True(t, req.NotifyAfter.IsUnset())
+
+	// false and empty lists are sent, so that resetting omitted settings
+	// doesn't change them
+	body, err := json.Marshal(req)
+	assert.Nil(t, err)
+	assert.Contains(t, string(body), `"always_alert_on_errors":false`)
+	assert.Contains(t, string(body), `"disabled_quality_check_ids":[]`)
+}
+
+func TestRunChecksResponseWireFormat(t *testing.T) {
+	assertRoundTrip(t, &RunChecksResponse{}, `{
+		"run_checks_job_id": "job",
+		"time_interval": {
+			"interval_id": 7,
+			"latest_run_checks_job_id": "job",
+			"status": "fail",
+			"time_period_start": "2023-01-01T00:00:00Z",
+			"time_period_end": "2023-01-02T00:00:00Z"
+		},
+		"check_runs": [{
+			"check_id": 1,
+			"check_run_id": 9,
+			"completed_at": "2023-01-02T01:00:00Z",
+			"created": "2023-01-02T00:00:00Z",
+			"CreatedBy": {"id": 3, "name": "creator"},
+			"last_edited_at": "2023-01-02T01:00:00Z",
+			"last_edited_by": {"id": 3, "name": "creator"},
+			"results": {"success": true, "statistic": 1.5},
+			"run_config": {"_metadata": {"check_type": "NullCheck"}, "check": "NullCheck", "check_id": 1},
+			"status": "pass"
+		}]
+	}`)
+}
@@ -0,0 +1,173 @@
+package anomalo
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// ToConfigureTableRequest Converts a table's current configuration into a
+// ConfigureTableRequest that would leave the table unchanged. Modify the
+// result to change some settings without resetting the others.
+//
+// Boolean and list settings are always set, so that false and empty values
+// survive a ConfigureTable call that resets omitted settings. Other settings
+// with empty values are left unset, except an empty CheckCadenceType, which
+// is Null because the table is not monitored.
+func (tc *TableConfig) ToConfigureTableRequest() ConfigureTableRequest {
+	req := ConfigureTableRequest{
+		TableID:                   tc.TableID,
+		CheckCadenceType:          setUnlessEmpty(tc.CheckCadenceType),
+		Definition:                setUnlessEmpty(tc.Definition),
+		TimeColumnType:            setUnlessEmpty(tc.TimeColumnType),
+		NotifyAfter:               setUnlessEmpty(tc.NotifyAfter),
+		NotificationChannelID:     setUnlessEmpty(tc.NotificationChannelID),
+		TimeColumns:               Set(tc.TimeColumns),
+		FreshAfter:                setUnlessEmpty(tc.FreshAfter),
+		CheckCadenceRunAtDuration: setUnlessEmpty(tc.CheckCadenceRunAtDuration),
+		IntervalSkipExpr:          setUnlessEmpty(tc.IntervalSkipExpr),
+		AlwaysAlertOnErrors:       Set(tc.AlwaysAlertOnErrors),
+		DisabledQualityCheckIds:   Set(tc.DisabledQualityCheckIds),
+	}
+	if tc.CheckCadenceType == "" {
+		req.CheckCadenceType = Null[string]()
+	}
+	return req
+}
+
+func setUnlessEmpty[T any](v T) Optional[T] {
+	if isEmptyJSONValue(reflect.ValueOf(&v).Elem()) {
+		return Optional[T]{}
+	}
+	return Set(v)
+}
+
+// FieldChange One setting that differs between a table's current and desired
+// configuration. Field is the setting's JSON name. For list settings, Added
+// and Removed hold the elements that would be added or removed.
+type FieldChange struct {
+	Field   string
+	From    interface{}
+	To      interface{}
+	Added   []string
+	Removed []string
+}
+
+// TableConfigDiff The settings a ConfigureTable call would change. See
+// DiffTableConfig.
+type TableConfigDiff struct {
+	TableID int
+	Changes []FieldChange
+}
+
+// DiffTableConfig Compares a table's current configuration with the
+// configuration a ConfigureTableRequest would apply, field by field.
+//
+// Unset fields in `desired` leave the setting unchanged, and Null fields are
+// compared as empty.
+func DiffTableConfig(current TableConfig, desired ConfigureTableRequest) *TableConfigDiff {
+	diff := &TableConfigDiff{TableID: desired.TableID}
+	if diff.TableID == 0 {
+		diff.TableID = current.TableID
+	}
+
+	diff.compare("check_cadence_type", current.CheckCadenceType, desiredValue(desired.CheckCadenceType, current.CheckCadenceType))
+	diff.compare("definition", current.Definition, desiredValue(desired.Definition, current.Definition))
+	diff.compare("time_column_type", current.TimeColumnType, desiredValue(desired.TimeColumnType, current.TimeColumnType))
+	compareList(diff, "time_columns", current.TimeColumns, desiredValue(desired.TimeColumns, current.TimeColumns))
+	diff.compare("notify_after", current.NotifyAfter, desiredValue(desired.NotifyAfter, current.NotifyAfter))
+	diff.compare("fresh_after", current.FreshAfter, desiredValue(desired.FreshAfter, current.FreshAfter))
+	diff.compare("check_cadence_run_at_duration", current.CheckCadenceRunAtDuration,
+		desiredValue(desired.CheckCadenceRunAtDuration, current.CheckCadenceRunAtDuration))
+	diff.compare("interval_skip_expr", current.IntervalSkipExpr, desiredValue(desired.IntervalSkipExpr, current.IntervalSkipExpr))
+	diff.compare("always_alert_on_errors", current.AlwaysAlertOnErrors,
+		desiredValue(desired.AlwaysAlertOnErrors, current.AlwaysAlertOnErrors))
+	compareList(diff, "disabled_quality_check_ids", current.DisabledQualityCheckIds,
+		desiredValue(desired.DisabledQualityCheckIds, current.DisabledQualityCheckIds))
+	diff.compare("notification_channel_id", current.NotificationChannelID,
+		desiredValue(desired.NotificationChannelID, current.NotificationChannelID))
+	return diff
+}
+
+// desiredValue The value a setting will have once `desired` is applied.
+func desiredValue[T any](desired Optional[T], current T) T {
+	if desired.IsUnset() {
+		return current
+	}
+	value, _ := desired.Get() // The zero value when Null
+	return value
+}
+
+func (d *TableConfigDiff) compare(field string, from interface{}, to interface{}) {
+	if from != to {
+		d.Changes = append(d.Changes, FieldChange{Field: field, From: from, To: to})
+	}
+}
+
+func compareList[T comparable](d *TableConfigDiff, field string, from []T, to []T) {
+	if slices.Equal(from, to) {
+		return
+	}
+	change := FieldChange{Field: field, From: from, To: to}
+	for _, value := range to {
+		if !slices.Contains(from, value) {
+			change.Added = append(change.Added, fmt.Sprint(value))
+		}
+	}
+	for _, value := range from {
+		if !slices.Contains(to, value) {
+			change.Removed = append(change.Removed, fmt.Sprint(value))
+		}
+	}
+	d.Changes = append(d.Changes, change)
+}
+
+// Empty Reports whether applying the desired configuration changes nothing.
+func (d *TableConfigDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// String Renders the diff as text, one changed setting per line, e.g.
+//
+//	table 5: 2 change(s)
+//	  check_cadence_type: "daily" -> "hourly"
+//	  time_columns: ["created_at"] -> ["created_at" "updated_at"] (+updated_at)
+func (d *TableConfigDiff) String() string {
+	if d.Empty() {
+		return fmt.Sprintf("table %d: no changes", d.TableID)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "table %d: %d change(s)", d.TableID, len(d.Changes))
+	for _, change := range d.Changes {
+		fmt.Fprintf(&b, "\n  %s: %s -> %s", change.Field, formatDiffValue(change.From), formatDiffValue(change.To))
+		var details []string
+		for _, added := range change.Added {
+			details = append(details, "+"+added)
+		}
+		for _, removed := range change.Removed {
+			details = append(details, "-"+removed)
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(details, ", "))
+		}
+	}
+	return b.String()
+}
+
+func formatDiffValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []string:
+		quoted := make([]string, len(v))
+		for i, s := range v {
+			quoted[i] = strconv.Quote(s)
+		}
+		return "[" + strings.Join(quoted, " ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
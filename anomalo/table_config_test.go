@@ -0,0 +1,56 @@
+package anomalo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTableConfig(t *testing.T) {
+	current := TableConfig{
+		TableID:                 5,
+		CheckCadenceType:        "daily",
+		TimeColumns:             []string{"created_at"},
+		NotifyAfter:             "1h",
+		DisabledQualityCheckIds: []int{1, 2},
+		NotificationChannelID:   6,
+	}
+
+	desired := current.ToConfigureTableRequest()
+	assert.True(t, DiffTableConfig(current, desired).Empty())
+	assert.Equal(t, "table 5: no changes", DiffTableConfig(current, desired).String())
+
+	desired.CheckCadenceType = Set("hourly")
+	desired.TimeColumns = Set([]string{"created_at", "updated_at"})
+	desired.DisabledQualityCheckIds = Set([]int{2, 3})
+	desired.NotificationChannelID = Set(8)
+	desired.NotifyAfter = Null[string]()
+	desired.FreshAfter = Optional[string]{} // Unset, so unchanged
+
+	diff := DiffTableConfig(current, desired)
+	assert.Equal(t, []FieldChange{
+		{Field: "check_cadence_type", From: "daily", To: "hourly"},
+		{
+			Field: "time_columns",
+			From:  []string{"created_at"},
+			To:    []string{"created_at", "updated_at"},
+			Added: []string{"updated_at"},
+		},
+		{Field: "notify_after", From: "1h", To: ""},
+		{
+			Field:   "disabled_quality_check_ids",
+			From:    []int{1, 2},
+			To:      []int{2, 3},
+			Added:   []string{"3"},
+			Removed: []string{"1"},
+		},
+		{Field: "notification_channel_id", From: 6, To: 8},
+	}, diff.Changes)
+
+	assert.Equal(t, `table 5: 5 change(s)
+  check_cadence_type: "daily" -> "hourly"
+  time_columns: ["created_at"] -> ["created_at" "updated_at"] (+updated_at)
+  notify_after: "1h" -> ""
+  disabled_quality_check_ids: [1 2] -> [2 3] (+3, -1)
+  notification_channel_id: 6 -> 8`, diff.String())
+}
@@ -0,0 +1,249 @@
+package anomalo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/exp/maps"
+)
+
+// GenerateTerraform Writes Terraform configuration for the given tables and
+// their checks, as read from Anomalo, to `w`. See WriteTerraform for what is
+// generated.
+func (c *Client) GenerateTerraform(w io.Writer, tables ...GetTableInformationRequest) error {
+	names := map[string]bool{}
+	for i, ref := range tables {
+		table, err := c.GetTableInformationFromRequest(ref)
+		if err != nil {
+			return err
+		}
+		checks, err := c.GetChecks(table.ID)
+		if err != nil {
+			return fmt.Errorf("unable to list checks for table %d. %w", table.ID, err)
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if err := writeTerraform(w, table, checks.Checks, names); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteTerraform Writes an `anomalo_table` resource for `table` and an
+// `anomalo_check` resource for each of `checks`, each preceded by an `import`
+// block, so that `terraform plan` adopts the existing objects instead of
+// creating new ones. Tables are imported by table ID, and checks by
+// "<table ID>,<check static ID>".
+//
+// System checks, which Anomalo creates on its own, are left out. Only settings
+// that are set are written.
+func WriteTerraform(w io.Writer, table *GetTableResponse, checks []Check) error {
+	return writeTerraform(w, table, checks, map[string]bool{})
+}
+
+// writeTerraform Keeps resource names unique across the tables in `names`.
+func writeTerraform(w io.Writer, table *GetTableResponse, checks []Check, names map[string]bool) error {
+	var b strings.Builder
+	tableName := uniqueTerraformName(terraformName(table.FullName, fmt.Sprintf("table_%d", table.ID)), names)
+	writeTerraformImport(&b, "anomalo_table."+tableName, strconv.Itoa(table.ID))
+
+	config := table.Config
+	var attrs []hclAttribute
+	attrs = appendHCLString(attrs, "table_name", table.FullName)
+	attrs = appendHCLString(attrs, "check_cadence_type", config.CheckCadenceType)
+	attrs = appendHCLString(attrs, "check_cadence_run_at_duration", config.CheckCadenceRunAtDuration)
+	attrs = appendHCLString(attrs, "definition", config.Definition)
+	attrs = appendHCLString(attrs, "time_column_type", config.TimeColumnType)
+	if len(config.TimeColumns) > 0 {
+		attrs = append(attrs, hclAttribute{"time_columns", hclList(config.TimeColumns)})
+	}
+	attrs = appendHCLString(attrs, "notify_after", config.NotifyAfter)
+	attrs = appendHCLString(attrs, "fresh_after", config.FreshAfter)
+	attrs = appendHCLString(attrs, "interval_skip_expr", config.IntervalSkipExpr)
+	if config.NotificationChannelID != 0 {
+		attrs = append(attrs, hclAttribute{"notification_channel_id", strconv.Itoa(config.NotificationChannelID)})
+	}
+	if config.AlwaysAlertOnErrors {
+		attrs = append(attrs, hclAttribute{"always_alert_on_errors", "true"})
+	}
+	if len(config.DisabledQualityCheckIds) > 0 {
+		ids := make([]string, len(config.DisabledQualityCheckIds))
+		for i, id := range config.DisabledQualityCheckIds {
+			ids[i] = strconv.Itoa(id)
+		}
+		attrs = append(attrs, hclAttribute{"disabled_quality_check_ids", "[" + strings.Join(ids, ", ") + "]"})
+	}
+	writeHCLBlock(&b, `resource "anomalo_table" "`+tableName+`"`, attrs)
+
+	for _, check := range checks {
+		if check.Config.Metadata.IsSystemCheck {
+			continue
+		}
+		suffix := check.Ref
+		if suffix == "" {
+			suffix = fmt.Sprintf("check_%d", check.CheckStaticID)
+		}
+		checkName := uniqueTerraformName(tableName+"_"+terraformName(suffix, "check"), names)
+		b.WriteString("\n")
+		writeTerraformImport(&b, "anomalo_check."+checkName, fmt.Sprintf("%d,%d", table.ID, check.CheckStaticID))
+
+		checkType := check.CheckType
+		if checkType == "" {
+			checkType = check.Config.Check
+		}
+		attrs := []hclAttribute{{"table_id", "anomalo_table." + tableName + ".id"}}
+		attrs = appendHCLString(attrs, "check_type", checkType)
+		attrs = appendHCLString(attrs, "ref", check.Ref)
+		if len(check.Config.Params) > 0 {
+			params, err := hclParams(check.Config.Params)
+			if err != nil {
+				return fmt.Errorf("check %d: %w", check.CheckStaticID, err)
+			}
+			attrs = append(attrs, hclAttribute{"params", params})
+		}
+		writeHCLBlock(&b, `resource "anomalo_check" "`+checkName+`"`, attrs)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeTerraformImport(b *strings.Builder, to string, id string) {
+	writeHCLBlock(b, "import", []hclAttribute{{"to", to}, {"id", hclString(id)}})
+	b.WriteString("\n")
+}
+
+// terraformName Converts `name` to a valid Terraform identifier, e.g.
+// "wh.schema.table" to "wh_schema_table".
+func terraformName(name string, fallback string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	id := strings.Trim(b.String(), "_")
+	if id == "" {
+		return fallback
+	}
+	if id[0] >= '0' && id[0] <= '9' || id[0] == '-' {
+		id = "_" + id
+	}
+	return id
+}
+
+func uniqueTerraformName(name string, names map[string]bool) string {
+	unique := name
+	for i := 2; names[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	names[unique] = true
+	return unique
+}
+
+// hclAttribute An attribute with its value already rendered as HCL.
+type hclAttribute struct {
+	name  string
+	value string
+}
+
+// writeHCLBlock Writes a block, aligning the `=` of consecutive single-line
+// attributes the way `terraform fmt` does.
+func writeHCLBlock(b *strings.Builder, header string, attrs []hclAttribute) {
+	b.WriteString(header + " {\n")
+	for start := 0; start < len(attrs); {
+		if strings.Contains(attrs[start].value, "\n") {
+			fmt.Fprintf(b, "  %s = %s\n", attrs[start].name, strings.ReplaceAll(attrs[start].value, "\n", "\n  "))
+			start++
+			continue
+		}
+		end, width := start, 0
+		for ; end < len(attrs) && !strings.Contains(attrs[end].value, "\n"); end++ {
+			if len(attrs[end].name) > width {
+				width = len(attrs[end].name)
+			}
+		}
+		for _, attr := range attrs[start:end] {
+			fmt.Fprintf(b, "  %-*s = %s\n", width, attr.name, attr.value)
+		}
+		start = end
+	}
+	b.WriteString("}\n")
+}
+
+func appendHCLString(attrs []hclAttribute, name string, value string) []hclAttribute {
+	if value == "" {
+		return attrs
+	}
+	return append(attrs, hclAttribute{name, hclString(value)})
+}
+
+// hclString Quotes `s` as an HCL string literal. Only the escapes HCL knows
+// are used: \n, \r, \t, \" and \\, and \uNNNN for other control
+// characters. Template sequences are escaped so that values like SQL
+// containing "${" are kept literally.
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '"':
+			b.WriteString(`\"`)
+		case r == '\\':
+			b.WriteString(`\\`)
+		case unicode.IsControl(r):
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	quoted := strings.ReplaceAll(b.String(), "${", "$${")
+	return strings.ReplaceAll(quoted, "%{", "%%{")
+}
+
+func hclList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = hclString(value)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// hclParams Renders check params as an HCL map of strings, matching
+// CreateCheckRequest.Params. Values that are not strings are JSON encoded.
+func hclParams(params map[string]interface{}) (string, error) {
+	keys := maps.Keys(params)
+	sort.Strings(keys)
+	var attrs []hclAttribute
+	for _, key := range keys {
+		value, ok := params[key].(string)
+		if !ok {
+			encoded, err := json.Marshal(params[key])
+			if err != nil {
+				return "", fmt.Errorf("unable to encode param %s. %w", key, err)
+			}
+			value = string(encoded)
+		}
+		attrs = append(attrs, hclAttribute{hclString(key), hclString(value)})
+	}
+	var b strings.Builder
+	writeHCLBlock(&b, "", attrs)
+	return strings.TrimSuffix(strings.TrimPrefix(b.String(), " "), "\n"), nil
+}
@@ -0,0 +1,104 @@
+package anomalo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteTerraform(t *testing.T) {
+	table := &GetTableResponse{
+		ID:       5,
+		FullName: "wh.sales.Orders",
+		Config: TableConfig{
+			CheckCadenceType:        "daily",
+			TimeColumns:             []string{"created_at"},
+			IntervalSkipExpr:        "${weekday} = 0",
+			NotificationChannelID:   8,
+			DisabledQualityCheckIds: []int{3, 4},
+		},
+	}
+	checks := []Check{
+		{CheckStaticID: 11, CheckType: "RowCount", Config: CheckConfig{Metadata: CheckMetadata{IsSystemCheck: true}}},
+		{CheckStaticID: 12, CheckType: "NullCheck", Ref: "no-null-ids", Config: CheckConfig{
+			Params: map[string]interface{}{"column_name": "id", "max_fraction": 0.1},
+		}},
+		{CheckStaticID: 13, Config: CheckConfig{Check: "Freshness"}},
+	}
+
+	var b strings.Builder
+	assert.Nil(t, WriteTerraform(&b, table, checks))
+	assert.Equal(t, `import {
+  to = anomalo_table.wh_sales_orders
+  id = "5"
+}
+
+resource "anomalo_table" "wh_sales_orders" {
+  table_name                 = "wh.sales.Orders"
+  check_cadence_type         = "daily"
+  time_columns               = ["created_at"]
+  interval_skip_expr         = "$${weekday} = 0"
+  notification_channel_id    = 8
+  disabled_quality_check_ids = [3, 4]
+}
+
+import {
+  to = anomalo_check.wh_sales_orders_no-null-ids
+  id = "5,12"
+}
+
+resource "anomalo_check" "wh_sales_orders_no-null-ids" {
+  table_id   = anomalo_table.wh_sales_orders.id
+  check_type = "NullCheck"
+  ref        = "no-null-ids"
+  params = {
+    "column_name"  = "id"
+    "max_fraction" = "0.1"
+  }
+}
+
+import {
+  to = anomalo_check.wh_sales_orders_check_13
+  id = "5,13"
+}
+
+resource "anomalo_check" "wh_sales_orders_check_13" {
+  table_id   = anomalo_table.wh_sales_orders.id
+  check_type = "Freshness"
+}
+`, b.String())
+}
+
+func TestHCLString(t *testing.T) {
+	assert.Equal(t, `"a\nb\r\t\"q\" \\ $${x} %%{y}"`, hclString("a\nb\r\t\"q\" \\ ${x} %{y}"))
+	assert.Equal(t, `"\u001b[0m \u0007 \u000b \u007f é ☃"`, hclString("\x1b[0m \a \v \x7f é ☃"))
+}
+
+func TestGenerateTerraformKeepsNamesUnique(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/get_table_information":
+			// Both tables sanitize to the same name
+			if r.URL.Query().Get("table_id") == "1" {
+				w.Write([]byte(`{"id": 1, "full_name": "wh.a.b"}`))
+			} else {
+				w.Write([]byte(`{"id": 2, "full_name": "wh.a_b"}`))
+			}
+		case "/api/public/v1/get_checks_for_table":
+			w.Write([]byte(`{"checks": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := &Client{Host: server.URL}
+
+	var b strings.Builder
+	err := client.GenerateTerraform(&b, GetTableInformationRequest{TableID: 1}, GetTableInformationRequest{TableID: 2})
+	assert.Nil(t, err)
+	assert.Contains(t, b.String(), `resource "anomalo_table" "wh_a_b" {`)
+	assert.Contains(t, b.String(), `resource "anomalo_table" "wh_a_b_2" {`)
+}
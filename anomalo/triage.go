@@ -0,0 +1,75 @@
+package anomalo
+
+import (
+	"context"
+	"net/http"
+)
+
+// UpdateTriageStatus Sets the triage status of a check run, e.g. to
+// acknowledge a failure. Returns the updated check run.
+func (c *Client) UpdateTriageStatus(req UpdateTriageStatusRequest) (*CheckRun, error) {
+	return do[UpdateTriageStatusRequest, CheckRun](context.Background(), c, http.MethodPost, "update_triage_status", &req)
+}
+
+// GetCheckRunsByTriageStatus Wrapper around GetCheckRuns that only returns the
+// runs of one interval with triage status `status`. Runs that were never
+// triaged count as TriageStatusUntriaged.
+func (c *Client) GetCheckRunsByTriageStatus(tableID int, intervalID int, status string) ([]CheckRun, error) {
+	data, err := c.GetCheckRuns(GetCheckRunsRequest{TableID: tableID, IntervalID: intervalID, TriageStatus: status})
+	if err != nil {
+		return nil, err
+	}
+	// Filter again in case the server returned runs with other statuses
+	var runs []CheckRun
+	for _, run := range data.CheckRuns {
+		if run.Triage() == status {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// AcknowledgeFailingRuns Acknowledges every failing check run of one interval
+// that has not been triaged yet, attaching `note` to each. Runs already
+// acknowledged, marked as expected or resolved are left alone.
+//
+// Returns the runs that were sent for acknowledgement along with the result
+// for each, in the same order. As with the other bulk operations, every run is
+// attempted and a *BulkError is returned if any failed.
+func (c *Client) AcknowledgeFailingRuns(
+	tableID int,
+	intervalID int,
+	note string,
+	opts BulkOptions,
+) ([]CheckRun, []BulkResult[CheckRun], error) {
+	data, err := c.GetCheckRuns(GetCheckRunsRequest{TableID: tableID, IntervalID: intervalID})
+	if err != nil {
+		return nil, nil, err
+	}
+	var runs []CheckRun
+	var reqs []UpdateTriageStatusRequest
+	for _, run := range data.CheckRuns {
+		if !run.Failed() || run.Triage() != TriageStatusUntriaged {
+			continue
+		}
+		runs = append(runs, run)
+		reqs = append(reqs, UpdateTriageStatusRequest{
+			TableID:      tableID,
+			CheckRunID:   run.CheckRunID,
+			TriageStatus: TriageStatusAcknowledged,
+			Note:         note,
+		})
+	}
+	results := make([]BulkResult[CheckRun], len(reqs))
+	runBulk(reqs, nil, results, opts, c.UpdateTriageStatus)
+	return runs, results, bulkError(results)
+}
+
+// Triage Returns the run's triage status, or TriageStatusUntriaged if it has
+// none.
+func (r *CheckRun) Triage() string {
+	if r.TriageStatus == nil || *r.TriageStatus == "" {
+		return TriageStatusUntriaged
+	}
+	return *r.TriageStatus
+}
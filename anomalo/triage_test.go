@@ -0,0 +1,70 @@
+package anomalo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const triageRuns = `{"check_runs": [
+	{"check_id": 1, "check_run_id": 11, "results": {"success": true}},
+	{"check_id": 2, "check_run_id": 12, "status": "fail", "results": {"success": false}},
+	{"check_id": 3, "check_run_id": 13, "status": "fail", "results": {"success": false}, "triage_status": "expected"},
+	{"check_id": 4, "check_run_id": 14, "results": {"errored": true}, "triage_status": null},
+	{"check_id": 5, "check_run_id": 15, "results_pending": true},
+	{"check_id": 6, "check_run_id": 16, "status": "skipped", "results": {"success": false}}
+]}`
+
+func TestGetCheckRunsByTriageStatus(t *testing.T) {
+	server := setupServer(t, "get_check_runs?interval_id=9&table_id=5&triage_status=expected", triageRuns, http.StatusOK)
+	defer server.Close()
+	client := &Client{Host: server.URL}
+
+	runs, err := client.GetCheckRunsByTriageStatus(5, 9, TriageStatusExpected)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 1)
+	assert.Equal(t, 13, runs[0].CheckRunID)
+}
+
+func TestAcknowledgeFailingRuns(t *testing.T) {
+	var mu sync.Mutex
+	var updates []UpdateTriageStatusRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/get_check_runs":
+			w.Write([]byte(triageRuns))
+		case "/api/public/v1/update_triage_status":
+			body, _ := io.ReadAll(r.Body)
+			var req UpdateTriageStatusRequest
+			assert.Nil(t, json.Unmarshal(body, &req))
+			mu.Lock()
+			updates = append(updates, req)
+			mu.Unlock()
+			fmt.Fprintf(w, `{"check_run_id": %d, "triage_status": %q}`, req.CheckRunID, req.TriageStatus)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := &Client{Host: server.URL}
+
+	runs, results, err := client.AcknowledgeFailingRuns(5, 9, "investigating", BulkOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, runs, 2)
+	assert.Equal(t, 12, runs[0].CheckRunID)
+	assert.Equal(t, 14, runs[1].CheckRunID)
+	assert.Equal(t, TriageStatusAcknowledged, results[1].Response.Triage())
+
+	sort.Slice(updates, func(i, j int) bool { return updates[i].CheckRunID < updates[j].CheckRunID })
+	assert.Equal(t, []UpdateTriageStatusRequest{
+		{TableID: 5, CheckRunID: 12, TriageStatus: TriageStatusAcknowledged, Note: "investigating"},
+		{TableID: 5, CheckRunID: 14, TriageStatus: TriageStatusAcknowledged, Note: "investigating"},
+	}, updates)
+}
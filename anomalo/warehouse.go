@@ -0,0 +1,209 @@
+package anomalo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WarehouseRefreshKind Distinguishes a full warehouse refresh, which re-crawls
+// every schema, from a partial refresh that only looks for new tables.
+type WarehouseRefreshKind int
+
+const (
+	FullRefresh WarehouseRefreshKind = iota
+	NewTablesRefresh
+)
+
+const (
+	// DefaultWarehousePollInterval How often PollWarehouseRefresh and
+	// WaitForWarehouseRefresh check on a refresh when no interval is given.
+	DefaultWarehousePollInterval = 10 * time.Second
+	// DefaultWarehouseRefreshTimeout How long WaitForWarehouseRefresh waits
+	// when no timeout is given.
+	DefaultWarehouseRefreshTimeout = 30 * time.Minute
+)
+
+// WaitForWarehouseRefreshOptions Controls WaitForWarehouseRefresh.
+type WaitForWarehouseRefreshOptions struct {
+	PollInterval time.Duration
+	Timeout      time.Duration
+}
+
+// WarehouseRefreshResult The outcome of WaitForWarehouseRefresh.
+type WarehouseRefreshResult struct {
+	// Warehouse The warehouse's state once the refresh finished.
+	Warehouse *Warehouse
+	// NewTables Tables that were not in the warehouse before the refresh.
+	NewTables []Table
+}
+
+func (c *Client) GetWarehouse(warehouseId int64) (*Warehouse, error) {
+	return c.getWarehouse(context.Background(), warehouseId)
+}
+
+func (c *Client) getWarehouse(ctx context.Context, warehouseId int64) (*Warehouse, error) {
+	endpoint := fmt.Sprintf("warehouse/%d", warehouseId)
+	return do[struct{}, Warehouse](ctx, c, http.MethodGet, endpoint, nil)
+}
+
+func (c *Client) CreateWarehouse(req CreateWarehouseRequest) (*Warehouse, error) {
+	return do[CreateWarehouseRequest, Warehouse](context.Background(), c, http.MethodPost, "warehouse", &req)
+}
+
+func (c *Client) UpdateWarehouse(req UpdateWarehouseRequest) (*Warehouse, error) {
+	endpoint := fmt.Sprintf("warehouse/%d", req.ID)
+	return do[UpdateWarehouseRequest, Warehouse](context.Background(), c, http.MethodPut, endpoint, &req)
+}
+
+// SetSchemaCrawlLists Replaces a warehouse's schema crawl inclusion and
+// exclusion lists. Both lists are always sent, so a nil or empty list clears
+// the corresponding list.
+func (c *Client) SetSchemaCrawlLists(warehouseId int64, inclusion []string, exclusion []string) (*Warehouse, error) {
+	if inclusion == nil {
+		inclusion = []string{}
+	}
+	if exclusion == nil {
+		exclusion = []string{}
+	}
+	return c.UpdateWarehouse(UpdateWarehouseRequest{
+		ID:                       int(warehouseId),
+		SchemaCrawlInclusionList: Set(inclusion),
+		SchemaCrawlExclusionList: Set(exclusion),
+	})
+}
+
+// RefreshWarehouse Starts a full refresh, which re-crawls every schema in the
+// warehouse. Use DiscoverNewWarehouseTables to only look for new tables.
+func (c *Client) RefreshWarehouse(warehouseId int64) (*Warehouse, error) {
+	endpoint := fmt.Sprintf("warehouse/%d/refresh", warehouseId)
+	return do[struct{}, Warehouse](context.Background(), c, http.MethodPost, endpoint, nil)
+}
+
+// ListTables Lists the tables Anomalo knows about in a warehouse, whether or
+// not they are monitored.
+func (c *Client) ListTables(req ListTablesRequest) (*ListTablesResponse, error) {
+	return c.listTables(context.Background(), req)
+}
+
+func (c *Client) listTables(ctx context.Context, req ListTablesRequest) (*ListTablesResponse, error) {
+	return do[ListTablesRequest, ListTablesResponse](ctx, c, http.MethodGet, "list_tables", &req)
+}
+
+// RefreshInProgress Reports whether a refresh of the given kind has started
+// and not yet finished, by comparing the warehouse's refresh timestamps.
+func (w *Warehouse) RefreshInProgress(kind WarehouseRefreshKind) bool {
+	if kind == NewTablesRefresh {
+		return w.LastPartialRefreshed.Before(w.LastPartialRefreshStarted)
+	}
+	return w.LastRefreshed.Before(w.LastRefreshStarted)
+}
+
+// PollWarehouseRefresh Checks on a warehouse every `interval` until the
+// refresh of the given kind has finished, then returns the warehouse's final
+// state.
+//
+// Returns an error as soon as a request for the warehouse fails, wrapped as
+// described on do, or ctx.Err() if `ctx` is done while waiting between polls.
+func (c *Client) PollWarehouseRefresh(
+	ctx context.Context,
+	warehouseId int64,
+	kind WarehouseRefreshKind,
+	interval time.Duration,
+) (*Warehouse, error) {
+	return c.pollWarehouse(ctx, warehouseId, interval, func(w *Warehouse) bool {
+		return !w.RefreshInProgress(kind)
+	})
+}
+
+// pollWarehouse Checks on a warehouse every `interval` until `done` reports
+// true for it.
+func (c *Client) pollWarehouse(
+	ctx context.Context,
+	warehouseId int64,
+	interval time.Duration,
+	done func(w *Warehouse) bool,
+) (*Warehouse, error) {
+	if interval <= 0 {
+		interval = DefaultWarehousePollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		warehouse, err := c.getWarehouse(ctx, warehouseId)
+		if err != nil {
+			return nil, err
+		}
+		if done(warehouse) {
+			return warehouse, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// WaitForWarehouseRefresh Looks for new tables in a warehouse and waits until
+// they are available.
+//
+// It triggers DiscoverNewWarehouseTables, polls the warehouse until the
+// refresh finishes or the timeout passes, and then returns the tables that
+// appeared during the refresh. The new tables can be passed straight to
+// ConfigureTable.
+//
+// The refresh counts as finished once the warehouse's LastPartialRefreshed is
+// at or after the LastPartialRefreshStarted that DiscoverNewWarehouseTables
+// returned, so a previous refresh that already finished is not mistaken for
+// this one. If Anomalo does not say when the refresh started, the time it was
+// requested is used instead. `ctx` and opts.Timeout bound every request.
+func (c *Client) WaitForWarehouseRefresh(
+	ctx context.Context,
+	warehouseId int64,
+	opts WaitForWarehouseRefreshOptions,
+) (*WarehouseRefreshResult, error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultWarehouseRefreshTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	before, err := c.listTables(ctx, ListTablesRequest{WarehouseID: int(warehouseId)})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list tables before refreshing warehouse %d. %w", warehouseId, err)
+	}
+	requested := time.Now()
+	discovered, err := c.discoverNewWarehouseTables(ctx, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+	started := discovered.LastPartialRefreshStarted
+	if started.IsZero() {
+		started = requested
+	}
+	warehouse, err := c.pollWarehouse(ctx, warehouseId, opts.PollInterval, func(w *Warehouse) bool {
+		return !w.LastPartialRefreshed.Before(started) && !w.RefreshInProgress(NewTablesRefresh)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("waiting for warehouse %d to refresh. %w", warehouseId, err)
+	}
+	after, err := c.listTables(ctx, ListTablesRequest{WarehouseID: int(warehouseId)})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list tables after refreshing warehouse %d. %w", warehouseId, err)
+	}
+
+	known := make(map[int]struct{}, len(before.Tables))
+	for _, table := range before.Tables {
+		known[table.ID] = struct{}{}
+	}
+	result := &WarehouseRefreshResult{Warehouse: warehouse}
+	for _, table := range after.Tables {
+		if _, ok := known[table.ID]; !ok {
+			result.NewTables = append(result.NewTables, table)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,164 @@
+package anomalo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetSchemaCrawlListsClearsLists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/public/v1/warehouse/3", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"schema_crawl_exclusion_list":[],"schema_crawl_inclusion_list":["sales"]}`, string(body))
+		w.Write([]byte(`{"id": 3, "schema_crawl_inclusion_list": ["sales"]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	warehouse, err := client.SetSchemaCrawlLists(3, []string{"sales"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"sales"}, warehouse.SchemaCrawlInclusionList)
+}
+
+func TestPollWarehouseRefresh(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/public/v1/warehouse/3", r.URL.Path)
+		if atomic.AddInt32(&polls, 1) < 3 {
+			w.Write([]byte(`{"id": 3, "last_refresh_started": "2023-01-02T00:00:00Z", "last_refreshed": "2023-01-01T00:00:00Z"}`))
+			return
+		}
+		w.Write([]byte(`{"id": 3, "last_refresh_started": "2023-01-02T00:00:00Z", "last_refreshed": "2023-01-02T00:05:00Z"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	warehouse, err := client.PollWarehouseRefresh(context.Background(), 3, FullRefresh, time.Millisecond)
+	assert.Nil(t, err)
+	assert.False(t, warehouse.RefreshInProgress(FullRefresh))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&polls))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	atomic.StoreInt32(&polls, 0)
+	_, err = client.PollWarehouseRefresh(ctx, 3, FullRefresh, time.Millisecond)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestWaitForWarehouseRefresh(t *testing.T) {
+	var refreshed int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/list_tables":
+			assert.Equal(t, "warehouse_id=3", r.URL.RawQuery)
+			if atomic.LoadInt32(&refreshed) == 0 {
+				w.Write([]byte(`{"tables": [{"id": 1, "full_name": "wh.s.old"}]}`))
+			} else {
+				w.Write([]byte(`{"tables": [{"id": 1, "full_name": "wh.s.old"}, {"id": 2, "full_name": "wh.s.new"}]}`))
+			}
+		case "/api/public/v1/warehouse/3/refresh/new":
+			w.Write([]byte(`{"id": 3, "last_partial_refresh_started": "2023-01-02T00:00:00Z"}`))
+		case "/api/public/v1/warehouse/3":
+			atomic.StoreInt32(&refreshed, 1)
+			w.Write([]byte(`{"id": 3, "last_partial_refresh_started": "2023-01-02T00:00:00Z", ` +
+				`"last_partial_refreshed": "2023-01-02T00:01:00Z"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	result, err := client.WaitForWarehouseRefresh(context.Background(), 3, WaitForWarehouseRefreshOptions{PollInterval: time.Millisecond})
+	assert.Nil(t, err)
+	assert.Equal(t, []Table{{ID: 2, FullName: "wh.s.new"}}, result.NewTables)
+}
+
+func TestWaitForWarehouseRefreshIgnoresPreviousRefresh(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/list_tables":
+			if atomic.LoadInt32(&polls) < 3 {
+				w.Write([]byte(`{"tables": [{"id": 1}]}`))
+			} else {
+				w.Write([]byte(`{"tables": [{"id": 1}, {"id": 2}]}`))
+			}
+		case "/api/public/v1/warehouse/3/refresh/new":
+			w.Write([]byte(`{"id": 3, "last_partial_refresh_started": "2023-01-02T00:00:00Z"}`))
+		case "/api/public/v1/warehouse/3":
+			// The refresh discovery started shows up late; until then the
+			// previous, finished refresh is reported
+			if atomic.AddInt32(&polls, 1) < 3 {
+				w.Write([]byte(`{"id": 3, "last_partial_refresh_started": "2023-01-01T00:00:00Z", ` +
+					`"last_partial_refreshed": "2023-01-01T00:01:00Z"}`))
+				return
+			}
+			w.Write([]byte(`{"id": 3, "last_partial_refresh_started": "2023-01-02T00:00:00Z", ` +
+				`"last_partial_refreshed": "2023-01-02T00:01:00Z"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	result, err := client.WaitForWarehouseRefresh(context.Background(), 3, WaitForWarehouseRefreshOptions{PollInterval: time.Millisecond})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&polls))
+	assert.Equal(t, []Table{{ID: 2}}, result.NewTables)
+}
+
+func TestWaitForWarehouseRefreshNeverRefreshed(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/v1/list_tables":
+			if atomic.LoadInt32(&polls) < 2 {
+				w.Write([]byte(`{"tables": []}`))
+			} else {
+				w.Write([]byte(`{"tables": [{"id": 1}]}`))
+			}
+		case "/api/public/v1/warehouse/3/refresh/new":
+			w.Write([]byte(`{"id": 3}`))
+		case "/api/public/v1/warehouse/3":
+			// Until the first refresh finishes, the warehouse has no refresh
+			// timestamps at all
+			if atomic.AddInt32(&polls, 1) < 2 {
+				w.Write([]byte(`{"id": 3}`))
+				return
+			}
+			fmt.Fprintf(w, `{"id": 3, "last_partial_refreshed": %q}`, time.Now().Format(time.RFC3339Nano))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	result, err := client.WaitForWarehouseRefresh(context.Background(), 3, WaitForWarehouseRefreshOptions{PollInterval: time.Millisecond})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&polls))
+	assert.Equal(t, []Table{{ID: 1}}, result.NewTables)
+}
+
+func TestWaitForWarehouseRefreshTimeoutCancelsRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done() // Only returns once the client gives up
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL}
+	_, err := client.WaitForWarehouseRefresh(context.Background(), 3, WaitForWarehouseRefreshOptions{Timeout: 20 * time.Millisecond})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}